@@ -0,0 +1,218 @@
+package period
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imarsman/datetime/xfmt"
+)
+
+// ISO-8601 alternative format carry-over points. In the alternative format no
+// component may exceed these.
+const (
+	alternativeMaxYears   = 9999
+	alternativeMaxMonths  = 12
+	alternativeMaxDays    = 30
+	alternativeMaxHours   = 24
+	alternativeMaxMinutes = 60
+	alternativeMaxSeconds = 60
+)
+
+// isAlternative reports whether a period string is in the ISO-8601
+// alternative format, i.e. it has no designators other than P and T.
+func isAlternative(input string) bool {
+	s := strings.TrimLeft(input, "+-")
+	if len(s) < 2 || (s[0] != periodChar && s[0] != 'p') {
+		return false
+	}
+
+	return strings.IndexAny(strings.ToUpper(s[1:]), "YMWDHS") < 0
+}
+
+// parseAlternative parses the ISO-8601 alternative format, either extended as
+// in "P0001-02-10T02:30:00" or basic as in "P00010210T023000". The seconds can
+// have a fraction. The time part is optional.
+func parseAlternative(input string) (Period, error) {
+	fail := func(reason string) (Period, error) {
+		xfmt := new(xfmt.Buffer)
+		xfmt.S("period.parse: ").S(input).S(" ").S(reason)
+
+		return Period{}, errors.New(string(xfmt.Bytes()))
+	}
+
+	s := input
+	var p Period
+	if s[0] == negativeChar || s[0] == plusChar {
+		p.negative = s[0] == negativeChar
+		s = s[1:]
+	}
+	s = s[1:] // period indicator
+
+	date, clock := s, ""
+	if t := strings.IndexAny(s, "Tt"); t >= 0 {
+		date, clock = s[:t], s[t+1:]
+		if clock == "" {
+			return fail("has no time after T in alternative format")
+		}
+	}
+
+	var values []string
+	switch {
+	case len(date) == 10 && date[4] == '-' && date[7] == '-':
+		values = []string{date[0:4], date[5:7], date[8:10]}
+	case len(date) == 8:
+		values = []string{date[0:4], date[4:6], date[6:8]}
+	default:
+		return fail("date must be YYYY-MM-DD or YYYYMMDD in alternative format")
+	}
+
+	var fraction string
+	if clock != "" {
+		if dot := strings.IndexAny(clock, ".,"); dot >= 0 {
+			clock, fraction = clock[:dot], clock[dot+1:]
+			if fraction == "" {
+				return fail("has no digits after the decimal point")
+			}
+		}
+
+		switch {
+		case len(clock) == 8 && clock[2] == ':' && clock[5] == ':':
+			values = append(values, clock[0:2], clock[3:5], clock[6:8])
+		case len(clock) == 6:
+			values = append(values, clock[0:2], clock[2:4], clock[4:6])
+		default:
+			return fail("time must be hh:mm:ss or hhmmss in alternative format")
+		}
+	}
+
+	var parts [6]int64
+	for i, v := range values {
+		for _, r := range v {
+			if r < '0' || r > '9' {
+				return fail("has a non-digit in alternative format")
+			}
+		}
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return Period{}, err
+		}
+		parts[i] = n
+	}
+
+	nanoseconds, err := scanFraction(fraction)
+	if err != nil {
+		return fail("has an invalid fraction")
+	}
+
+	p.years, p.months, p.days = parts[0], parts[1], parts[2]
+	p.hours, p.minutes, p.seconds = parts[3], parts[4], parts[5]
+	p.nanoseconds = nanoseconds
+
+	if err := p.checkCarryPoints(); err != nil {
+		return fail(err.Error())
+	}
+	if p.IsZero() {
+		p.negative = false
+	}
+
+	return p, nil
+}
+
+// checkCarryPoints checks that no component exceeds its carry-over point in
+// the alternative format.
+func (p Period) checkCarryPoints() error {
+	limits := []struct {
+		name  string
+		value int64
+		limit int64
+	}{
+		{"years", p.years, alternativeMaxYears},
+		{"months", p.months, alternativeMaxMonths},
+		{"days", p.weeks*7 + p.days, alternativeMaxDays},
+		{"hours", p.hours, alternativeMaxHours},
+		{"minutes", p.minutes, alternativeMaxMinutes},
+		{"seconds", p.seconds, alternativeMaxSeconds},
+	}
+
+	for _, l := range limits {
+		if l.value < 0 {
+			return fmt.Errorf("has negative %s, which the alternative format cannot hold", l.name)
+		}
+		if l.value > l.limit {
+			return fmt.Errorf("has %d %s, which exceeds the carry-over point of %d", l.value, l.name, l.limit)
+		}
+	}
+	if p.seconds == alternativeMaxSeconds && p.nanoseconds != 0 {
+		return fmt.Errorf("has seconds that exceed the carry-over point of %d", alternativeMaxSeconds)
+	}
+	if p.nanoseconds < 0 || p.nanoseconds >= int64(time.Second) {
+		return errors.New("has a fraction of a second out of range")
+	}
+
+	return nil
+}
+
+// FormatAlternative writes the period in the ISO-8601 alternative format,
+// extended as in "P0001-02-10T02:30:00" or, if basic is true, as in
+// "P00010210T023000". Any nanoseconds are written as a fraction of a second. A
+// negative period has a leading minus sign.
+//
+// An error is returned if any component exceeds its carry-over point of 9999
+// years, 12 months, 30 days, 24 hours, 60 minutes or 60 seconds, or if the
+// components have differing signs. Normalise the period first if need be.
+func (p Period) FormatAlternative(basic bool) (string, error) {
+	b, err := p.AppendAlternative(make([]byte, 0, 30), basic)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+// AppendAlternative appends the ISO-8601 alternative format of the period to
+// b, as with FormatAlternative, and returns the extended buffer.
+func (p Period) AppendAlternative(b []byte, basic bool) ([]byte, error) {
+	if err := p.checkCarryPoints(); err != nil {
+		xfmt := new(xfmt.Buffer)
+		xfmt.S("period.FormatAlternative: ").S(p.String()).S(" ").S(err.Error())
+
+		return b, errors.New(string(xfmt.Bytes()))
+	}
+
+	dateSeparator, timeSeparator := "-", ":"
+	if basic {
+		dateSeparator, timeSeparator = "", ""
+	}
+
+	if p.negative {
+		b = append(b, negativeChar)
+	}
+	b = append(b, periodChar)
+	b = append(appendPadded(b, p.years, 4), dateSeparator...)
+	b = append(appendPadded(b, p.months, 2), dateSeparator...)
+	b = append(appendPadded(b, p.weeks*7+p.days, 2), timeChar)
+	b = append(appendPadded(b, p.hours, 2), timeSeparator...)
+	b = append(appendPadded(b, p.minutes, 2), timeSeparator...)
+	b = appendPadded(b, p.seconds, 2)
+	if p.nanoseconds != 0 {
+		b = appendFraction(b, p.nanoseconds)
+	}
+
+	return b, nil
+}
+
+// appendPadded appends a non-negative value with leading zeros to the given
+// width.
+func appendPadded(b []byte, v int64, width int) []byte {
+	for limit := int64(10); width > 1; width-- {
+		if v < limit {
+			b = append(b, '0')
+		}
+		limit *= 10
+	}
+
+	return strconv.AppendInt(b, v, 10)
+}
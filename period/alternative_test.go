@@ -0,0 +1,128 @@
+package period_test
+
+import (
+	"testing"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+func TestParseAlternative(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"P0001-02-10T02:30:00", "P1Y2M10DT2H30M"},
+		{"P00010210T023000", "P1Y2M10DT2H30M"},
+		{"P0001-02-10", "P1Y2M10D"},
+		{"P00010210", "P1Y2M10D"},
+		{"-P0001-02-10T02:30:00", "-P1Y2M10DT2H30M"},
+		{"+P0000-00-00T00:00:01.5", "PT1.5S"},
+		{"P0000-00-00T00:00:00,25", "PT0.25S"},
+		{"-P0000-00-00T00:00:00", "P0D"},
+		{"P9999-12-30T24:60:60", "P9999Y12M30DT24H60M60S"},
+		{"p0001-02-10t02:30:00", "P1Y2M10DT2H30M"},
+	}
+
+	for i, test := range tests {
+		p, err := period.Parse(test.input)
+		is.NoErr(err)
+		is.Equal(info(i, p.String()), info(i, test.want))
+
+		// Alternative formats are part of ISO-8601, so strict mode allows them
+		_, err = period.ParseWithOptions(test.input, period.Strict)
+		is.NoErr(err)
+	}
+}
+
+func TestParseAlternativeBad(t *testing.T) {
+	is := is.New(t)
+
+	tests := []string{
+		"P0001-13-10T02:30:00",
+		"P0001-02-31T02:30:00",
+		"P0001-02-10T25:30:00",
+		"P0001-02-10T02:61:00",
+		"P0001-02-10T02:30:61",
+		"P0001-02-10T02:30:60.5",
+		"P0001-02-10T",
+		"P0001-02-10T02:30",
+		"P01-02-10T02:30:00",
+		"P0001-02-10T0230:00",
+		"P0001/02/10T02:30:00",
+		"P000102100T023000",
+		"P0001-02-10T02:30:00.",
+		"P1",
+	}
+
+	for i, test := range tests {
+		_, err := period.Parse(test)
+		t.Log(i, err)
+		is.True(err != nil) // bad alternative format
+	}
+}
+
+func TestFormatAlternative(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input    string
+		extended string
+		basic    string
+	}{
+		{"P1Y2M10DT2H30M", "P0001-02-10T02:30:00", "P00010210T023000"},
+		{"P0D", "P0000-00-00T00:00:00", "P00000000T000000"},
+		{"-P1Y2M", "-P0001-02-00T00:00:00", "-P00010200T000000"},
+		{"PT1.05S", "P0000-00-00T00:00:01.05", "P00000000T000001.05"},
+		{"P9999Y12M30DT24H60M60S", "P9999-12-30T24:60:60", "P99991230T246060"},
+		{"P2W", "P0000-00-14T00:00:00", "P00000014T000000"},
+	}
+
+	for i, test := range tests {
+		p := period.MustParse(test.input, false)
+
+		got, err := p.FormatAlternative(false)
+		is.NoErr(err)
+		is.Equal(info(i, got), info(i, test.extended))
+
+		got, err = p.FormatAlternative(true)
+		is.NoErr(err)
+		is.Equal(info(i, got), info(i, test.basic))
+
+		// Both forms parse back to the same period
+		back := period.MustParse(test.extended, false)
+		is.True(back.Equal(p))
+		back = period.MustParse(test.basic, false)
+		is.True(back.Equal(p))
+	}
+}
+
+func TestFormatAlternativeErrors(t *testing.T) {
+	is := is.New(t)
+
+	tests := []string{
+		"P10000Y",
+		"P13M",
+		"P31D",
+		"P5W",
+		"PT25H",
+		"PT61M",
+		"PT60.5S",
+		"P1Y-2M",
+	}
+
+	for i, test := range tests {
+		p := period.MustParse(test, false)
+		_, err := p.FormatAlternative(false)
+		t.Log(i, err)
+		is.True(err != nil) // component exceeds the carry-over point
+	}
+
+	// Normalising first can bring components within the limits
+	p := period.MustParse("P13MT90M", false)
+	got, err := p.Normalise(true).FormatAlternative(false)
+	is.NoErr(err)
+	is.Equal(got, "P0001-01-00T01:30:00")
+}
@@ -5,69 +5,341 @@
 package period
 
 import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
 	"time"
+
+	"github.com/cockroachdb/apd"
+	"github.com/imarsman/datetime/timestamp"
 )
 
-// Add adds two periods together. Use this method along with Negate in order to subtract periods.
+// Add adds two periods together, keeping the sign and nanoseconds of each.
+// If the sum overflows, the zero period is returned. It is kept for
+// compatibility; use AddWithOverflowCheck, which returns an error instead.
 //
-// The result is not normalised and may overflow arithmetically (to make this unlikely, use Normalise on
-// the inputs before adding them).
+// The result is not normalised except where values are carried between fields
+// with a fixed ratio to avoid differing signs; e.g. PT1H plus -PT1M gives PT59M
+// but P1M plus -P1D gives P1M-1D.
 func (p Period) Add(that Period) Period {
-	return NewPeriod(
-		p.Years()+that.Years(),
-		p.Months()+that.Months(),
-		p.Days()+that.Days(),
-		p.Hours()+that.Hours(),
-		p.Minutes()+that.Minutes(),
-		p.Seconds()+that.Seconds(),
-	)
+	result, _ := p.AddWithOverflowCheck(that)
+	return result
+}
+
+// AddWithOverflowCheck adds two periods together, keeping the sign and
+// nanoseconds of each.
+//
+// Where the sums of the fields would overflow or would have differing signs,
+// values are carried between fields that have a fixed ratio: years and
+// months, weeks and days, and hours, minutes, seconds and nanoseconds. Days
+// are never carried into hours or months, so the result can have components
+// with differing signs, e.g. P1M minus P1D is P1M-1D. An error is returned if
+// the result overflows.
+func (p Period) AddWithOverflowCheck(that Period) (Period, error) {
+	a, b := p.signedFields(), that.signedFields()
+
+	var sums [8]int64
+	groups := []struct {
+		from, to int
+		ratios   []int64
+	}{
+		{0, 2, []int64{12}},                         // years, months
+		{2, 4, []int64{7}},                          // weeks, days
+		{4, 8, []int64{60, 60, int64(time.Second)}}, // hours, minutes, seconds, nanoseconds
+	}
+	for _, g := range groups {
+		group, err := addGroup(a[g.from:g.to], b[g.from:g.to], g.ratios)
+		if err != nil {
+			return Period{}, err
+		}
+		copy(sums[g.from:g.to], group)
+	}
+
+	return fromSignedFields(sums)
+}
+
+// Sub subtracts that period from this one, keeping the sign and nanoseconds
+// of each. If the difference overflows, the zero period is returned. It is
+// kept for compatibility; use SubWithOverflowCheck, which returns an error
+// instead.
+func (p Period) Sub(that Period) Period {
+	result, _ := p.SubWithOverflowCheck(that)
+	return result
+}
+
+// SubWithOverflowCheck subtracts that period from this one, following the
+// same rules as AddWithOverflowCheck.
+func (p Period) SubWithOverflowCheck(that Period) (Period, error) {
+	return p.AddWithOverflowCheck(*that.Negate())
+}
+
+// signedFields gives the field values with the sign of the period applied.
+func (p Period) signedFields() [8]int64 {
+	fields := p.fields()
+	if p.negative {
+		for i := range fields {
+			fields[i] = -fields[i]
+		}
+	}
+
+	return fields
+}
+
+// fromSignedFields makes a period from signed field values. Where no field is
+// positive the period is negative, with the fields as absolute values.
+// Otherwise fields with differing signs are kept as they are.
+func fromSignedFields(fields [8]int64) (Period, error) {
+	var positive, negative bool
+	for _, v := range fields {
+		positive = positive || v > 0
+		negative = negative || v < 0
+	}
+
+	negative = negative && !positive
+	if negative {
+		for i, v := range fields {
+			if v == math.MinInt64 {
+				return Period{}, errors.New("period: result exceeds maximum")
+			}
+			fields[i] = -v
+		}
+	}
+
+	return Period{
+		negative:    negative,
+		years:       fields[0],
+		months:      fields[1],
+		weeks:       fields[2],
+		days:        fields[3],
+		hours:       fields[4],
+		minutes:     fields[5],
+		seconds:     fields[6],
+		nanoseconds: fields[7],
+	}, nil
+}
+
+// addGroup adds a group of fields, ordered from the largest unit, in which
+// ratios[i] is the number of units of field i+1 in field i. The plain sums are
+// used when they all have the same sign and none overflows.
+func addGroup(a, b, ratios []int64) ([]int64, error) {
+	sums := make([]int64, len(a))
+
+	var positive, negative, overflowed bool
+	for i := range a {
+		sum, ok := timestamp.Int64Overflows(a[i], b[i])
+		overflowed = overflowed || !ok
+		positive = positive || sum > 0
+		negative = negative || sum < 0
+		sums[i] = sum
+	}
+	if !overflowed && !(positive && negative) {
+		return sums, nil
+	}
+
+	return carryGroup(a, b, ratios)
+}
+
+// carryGroup adds a group of fields exactly using arbitrary precision decimals,
+// as a total of the smallest unit, then splits the total back out so that
+// every field has the same sign.
+func carryGroup(a, b, ratios []int64) ([]int64, error) {
+	apdContext := apd.BaseContext.WithPrecision(200)
+
+	total := new(apd.Decimal)
+	scale := apd.New(1, 0)
+	term := new(apd.Decimal)
+	for i := len(a) - 1; i >= 0; i-- {
+		for _, v := range []int64{a[i], b[i]} {
+			if _, err := apdContext.Mul(term, apd.New(v, 0), scale); err != nil {
+				return nil, err
+			}
+			if _, err := apdContext.Add(total, total, term); err != nil {
+				return nil, err
+			}
+		}
+		if i > 0 {
+			if _, err := apdContext.Mul(scale, scale, apd.New(ratios[i-1], 0)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	sums := make([]int64, len(a))
+	remainder := new(apd.Decimal)
+	for i := len(a) - 1; i > 0; i-- {
+		ratio := apd.New(ratios[i-1], 0)
+		if _, err := apdContext.Rem(remainder, total, ratio); err != nil {
+			return nil, err
+		}
+		if _, err := apdContext.QuoInteger(total, total, ratio); err != nil {
+			return nil, err
+		}
+		sums[i], _ = remainder.Int64()
+	}
+
+	largest, err := total.Int64()
+	if err != nil {
+		return nil, errors.New("period: result exceeds maximum")
+	}
+	sums[0] = largest
+
+	return sums, nil
 }
 
 //-------------------------------------------------------------------------------------------------
 
-// AddTo adds the period to a time, returning the result.
-// A flag is also returned that is true when the conversion was precise and false otherwise.
+// EndOfMonth sets what happens when adding months to a date gives a day that
+// is beyond the end of the resulting month, e.g. 31st January plus one month.
+type EndOfMonth int
+
+const (
+	// EndOfMonthOverflow carries the excess days into the following month, as
+	// time.AddDate does; 31st January plus one month is 3rd March, or 2nd March
+	// in a leap year.
+	EndOfMonthOverflow EndOfMonth = iota
+	// EndOfMonthClamp uses the last day of the resulting month; 31st January
+	// plus one month is 28th February, or 29th February in a leap year.
+	EndOfMonthClamp
+	// EndOfMonthError gives an error instead.
+	EndOfMonthError
+)
+
+// AddTo adds the period to a time, returning the result. Months overflow into
+// the following month as with time.AddDate; use AddToWithEndOfMonth to choose
+// another policy.
 //
-// When the period specifies hours, minutes and seconds only, the result is precise.
-// Also, when the period specifies whole years, months and days (i.e. without fractions), the
-// result is precise. However, when years, months or days contains fractions, the result
-// is only an approximation (it assumes that all days are 24 hours and every year is 365.2425
-// days, as per Gregorian calendar rules).
-// TODO: test this
+// A flag is also returned that is true when the result is precise. As the
+// calendar of the time is used, this is always the case when there is no error.
 func (p Period) AddTo(t time.Time) (time.Time, bool, error) {
-	// wholeYears := (p.years % 1) == 0
-	// wholeMonths := (p.months % 1) == 0
-	// wholeDays := (p.days % 1) == 0
-
-	// if wholeYears && wholeMonths && wholeDays {
-	if p.years > 0 || p.months > 0 || p.days > 0 {
-		// in this case, time.AddDate provides an exact solution
-		stE3, err := hmsDuration(p)
-		if err != nil {
-			return time.Time{}, false, err
-		}
+	return p.AddToWithEndOfMonth(t, EndOfMonthOverflow)
+}
+
+// AddToWithEndOfMonth adds the period to a time, returning the result.
+//
+// Years and months are added to the calendar date first, applying the
+// end-of-month policy, then weeks and days are added to the calendar date
+// keeping the clock time, as with time.AddDate. Finally the hours, minutes,
+// seconds and nanoseconds are added as elapsed time. Negative periods move
+// back in time in the same way. Fractions in a parsed period, such as P1.5M,
+// are carried into the smaller fields when parsed, so are added as days and
+// elapsed time.
+//
+// A flag is also returned that is true when the result is precise. As the
+// calendar of the time is used, this is always the case when there is no error.
+func (p Period) AddToWithEndOfMonth(t time.Time, eom EndOfMonth) (time.Time, bool, error) {
+	fields := p.signedFields()
+
+	months, err := sumOf(fields[0], 12, fields[1])
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	days, err := sumOf(fields[2], 7, fields[3])
+	if err != nil {
+		return time.Time{}, false, err
+	}
 
-		// t1 := t.AddDate(int(p.years/10), int(p.months/10), int(p.days/10))
-		t1 := t.AddDate(int(p.years), int(p.months), int(p.days))
-		return t1.Add(stE3 * time.Millisecond), true, nil
+	t, err = addMonths(t, months, eom)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if days != 0 {
+		t = t.AddDate(0, 0, int(days))
 	}
 
-	d, precise, err := p.Duration()
+	hoursMinutes, err := sumOf(fields[4], 60, fields[5])
 	if err != nil {
 		return time.Time{}, false, err
 	}
+	seconds, err := sumOf(hoursMinutes, 60, fields[6])
+	if err != nil {
+		return time.Time{}, false, err
+	}
+
+	return addElapsed(t, seconds, fields[7])
+}
+
+// SubFrom subtracts the period from a time, returning the result. Months
+// overflow into the following month as with time.AddDate; use
+// SubFromWithEndOfMonth to choose another policy.
+func (p Period) SubFrom(t time.Time) (time.Time, bool, error) {
+	return p.SubFromWithEndOfMonth(t, EndOfMonthOverflow)
+}
+
+// SubFromWithEndOfMonth subtracts the period from a time, returning the
+// result. It is the same as adding the negated period with
+// AddToWithEndOfMonth.
+func (p Period) SubFromWithEndOfMonth(t time.Time, eom EndOfMonth) (time.Time, bool, error) {
+	return p.Negate().AddToWithEndOfMonth(t, eom)
+}
+
+// sumOf gives larger * ratio + smaller, or an error on overflow.
+func sumOf(larger, ratio, smaller int64) (int64, error) {
+	if larger > math.MaxInt64/ratio || larger < math.MinInt64/ratio {
+		return 0, errors.New("period: time offset exceeds maximum")
+	}
+	sum, ok := timestamp.Int64Overflows(larger*ratio, smaller)
+	if ok == false {
+		return 0, errors.New("period: time offset exceeds maximum")
+	}
+
+	return sum, nil
+}
+
+// addMonths adds months to the calendar date of t, keeping the clock time and
+// applying the end-of-month policy.
+func addMonths(t time.Time, months int64, eom EndOfMonth) (time.Time, error) {
+	if months == 0 {
+		return t, nil
+	}
+
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	month += time.Month(months)
 
-	return t.Add(d), precise, nil
+	// day zero of the following month is the last day of this one
+	last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if day > last {
+		switch eom {
+		case EndOfMonthClamp:
+			day = last
+		case EndOfMonthError:
+			return time.Time{}, fmt.Errorf("period: day %d is beyond the end of the month", day)
+		}
+	}
+
+	return time.Date(year, month, day, hour, minute, second, t.Nanosecond(), t.Location()), nil
 }
 
+// addElapsed adds seconds and nanoseconds of elapsed time to t.
+func addElapsed(t time.Time, seconds, nanoseconds int64) (time.Time, bool, error) {
+	if seconds > -maxDurationSeconds && seconds < maxDurationSeconds {
+		d, ok := timestamp.Int64Overflows(seconds*int64(time.Second), nanoseconds)
+		if ok {
+			return t.Add(time.Duration(d)), true, nil
+		}
+	}
+
+	// Too large for a time.Duration, so work from the Unix time
+	unix, ok := timestamp.Int64Overflows(t.Unix(), seconds, nanoseconds/int64(time.Second))
+	if ok == false {
+		return time.Time{}, false, errors.New("period: time offset exceeds maximum")
+	}
+	nanoseconds = int64(t.Nanosecond()) + nanoseconds%int64(time.Second)
+
+	return time.Unix(unix, nanoseconds).In(t.Location()), true, nil
+}
+
+// maxDurationSeconds is the number of whole seconds in the largest
+// time.Duration.
+const maxDurationSeconds = math.MaxInt64 / int64(time.Second)
+
 //-------------------------------------------------------------------------------------------------
 
 // Scale a period by a multiplication factor. Obviously, this can both enlarge and shrink it,
-// and change the sign if negative. The result is normalised, but integer overflows are silently
-// ignored.
-//
-// Bear in mind that the internal representation is limited by fixed-point arithmetic with two
-// decimal places; each field is only int16.
+// and change the sign if the factor is negative. Each field keeps its own sign, so P1Y-2M
+// scaled by 2 is P2Y-4M before it is normalised. The result is normalised. If the result
+// overflows, the zero period is returned; use ScaleWithOverflowCheck to detect this.
 //
 // Known issue: scaling by a large reduction factor (i.e. much less than one) doesn't work properly.
 func (p Period) Scale(factor float64) *Period {
@@ -76,13 +348,14 @@ func (p Period) Scale(factor float64) *Period {
 }
 
 // ScaleWithOverflowCheck a period by a multiplication factor. Obviously, this can both enlarge and shrink it,
-// and change the sign if negative. The result is normalised. An error is returned if integer overflow
-// happened.
+// and change the sign if the factor is negative. Each field keeps its own sign. The result is normalised.
+// An error is returned if integer overflow happened.
 //
-// Bear in mind that the internal representation is limited by fixed-point arithmetic with one
-// decimal place; each field is only int16.
+// Each field is scaled as a float64, so large values lose precision, and any fraction is
+// truncated rather than carried into smaller fields.
 //
 // Known issue: scaling by a large reduction factor (i.e. much less than one) doesn't work properly.
+// Use ScaleRat or ScaleDecimal for exact scaling.
 func (p Period) ScaleWithOverflowCheck(factor float64) (*Period, error) {
 	if -0.5 < factor && factor < 0.5 {
 		d, pr1, err := p.Duration()
@@ -95,17 +368,139 @@ func (p Period) ScaleWithOverflowCheck(factor float64) (*Period, error) {
 		return p2.Normalise(pr1 && pr2), nil
 	}
 
-	y := int64(float64(p.years) * factor)
-	m := int64(float64(p.months) * factor)
-	d := int64(float64(p.days) * factor)
-	hh := int64(float64(p.hours) * factor)
-	mm := int64(float64(p.minutes) * factor)
-	ss := int64(float64(p.seconds) * factor)
-	subsec := int64(float64(p.nanoseconds) * factor)
+	fields := p.signedFields()
+	for i, v := range fields {
+		scaled := float64(v) * factor
+		// float64(math.MaxInt64) rounds up to 2^63, which is out of range
+		if math.IsNaN(scaled) || scaled >= math.MaxInt64 || scaled < math.MinInt64 {
+			return &Period{}, errors.New("period.ScaleWithOverflowCheck: result exceeds maximum")
+		}
+		fields[i] = int64(scaled)
+	}
 
-	newPeriod := NewPeriod(y, m, d, hh, mm, ss)
-	newPeriod.nanoseconds = subsec
-	newPeriod.negative = p.IsNegative()
+	result, err := fromSignedFields(fields)
+	if err != nil {
+		return &Period{}, err
+	}
 
-	return newPeriod.Normalise(true), nil
+	return result.Normalise(true), nil
+}
+
+// MonthBasis selects the length of a month used when a fraction of a month is
+// carried into days, as when scaling with ScaleRat.
+type MonthBasis int
+
+const (
+	// AverageMonth is 1/12 of a year of 365.2425 days, or 30.436875 days, as
+	// with CompareApprox.
+	AverageMonth MonthBasis = iota
+	// ThirtyDayMonth is 30 days, as in 30/360 day count conventions.
+	ThirtyDayMonth
+	// CommonYearMonth is 1/12 of a year of 365 days, as with Duration.
+	CommonYearMonth
+)
+
+// days gives the number of days in a month.
+func (basis MonthBasis) days() *big.Rat {
+	switch basis {
+	case ThirtyDayMonth:
+		return big.NewRat(30, 1)
+	case CommonYearMonth:
+		return big.NewRat(365, 12)
+	}
+	return big.NewRat(daysPerMonthE6, oneE6)
+}
+
+// ScaleRat multiplies each field of the period by an exact rational factor.
+// Whole values stay in their fields and fractions are carried into the next
+// smaller field: years into months, months into days using the month basis,
+// weeks into days and so on down to nanoseconds, with any fraction of a
+// nanosecond truncated. So P1M scaled by 1/2 is P15DT5H14M33S with an average
+// month, or P15D with a thirty-day month, and PT1H scaled by 1/3 is PT20M.
+//
+// An error is returned if a field overflows.
+func (p Period) ScaleRat(factor *big.Rat, basis MonthBasis) (Period, error) {
+	fields := p.signedFields()
+
+	// the field into which each field's fraction is carried, and the ratio
+	carries := [7]struct {
+		into  int
+		ratio *big.Rat
+	}{
+		{1, big.NewRat(12, 1)},
+		{3, basis.days()},
+		{3, big.NewRat(7, 1)},
+		{4, big.NewRat(24, 1)},
+		{5, big.NewRat(60, 1)},
+		{6, big.NewRat(60, 1)},
+		{7, big.NewRat(int64(time.Second), 1)},
+	}
+
+	var values [8]*big.Rat
+	for i, v := range fields {
+		values[i] = new(big.Rat).Mul(big.NewRat(v, 1), factor)
+	}
+
+	for i, v := range values {
+		// Quo truncates towards zero, so the fraction has the sign of the value
+		whole := new(big.Int).Quo(v.Num(), v.Denom())
+		if !whole.IsInt64() {
+			return Period{}, errors.New("period: scaled value exceeds maximum")
+		}
+		fields[i] = whole.Int64()
+
+		if i < len(carries) {
+			fraction := new(big.Rat).Sub(v, new(big.Rat).SetInt(whole))
+			fraction.Mul(fraction, carries[i].ratio)
+			values[carries[i].into].Add(values[carries[i].into], fraction)
+		}
+	}
+
+	return fromSignedFields(fields)
+}
+
+// ScaleDecimal multiplies each field of the period by an exact decimal factor,
+// as with ScaleRat. An error is returned if the factor is not finite or a
+// field overflows.
+func (p Period) ScaleDecimal(factor *apd.Decimal, basis MonthBasis) (Period, error) {
+	if factor.Form != apd.Finite {
+		return Period{}, fmt.Errorf("period: cannot scale by %s", factor)
+	}
+
+	rat := new(big.Rat).SetInt(&factor.Coeff)
+	scale := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(absInt32(factor.Exponent))), nil)
+	if factor.Exponent < 0 {
+		rat.Quo(rat, new(big.Rat).SetInt(scale))
+	} else {
+		rat.Mul(rat, new(big.Rat).SetInt(scale))
+	}
+	if factor.Negative {
+		rat.Neg(rat)
+	}
+
+	return p.ScaleRat(rat, basis)
+}
+
+// Multiply multiplies each field of the period by n using integer arithmetic.
+// An error is returned if a field overflows.
+func (p Period) Multiply(n int64) (Period, error) {
+	return p.ScaleRat(big.NewRat(n, 1), AverageMonth)
+}
+
+// Divide divides the period by n exactly, carrying the remainder of each field
+// into smaller fields as with ScaleRat; e.g. P1D divided by 3 is PT8H. An
+// error is returned if n is zero.
+func (p Period) Divide(n int64, basis MonthBasis) (Period, error) {
+	if n == 0 {
+		return Period{}, errors.New("period: division by zero")
+	}
+	return p.ScaleRat(big.NewRat(1, n), basis)
+}
+
+// absInt32 gives the absolute value of an int32 as an int64.
+func absInt32(v int32) int64 {
+	if v < 0 {
+		return -int64(v)
+	}
+	return int64(v)
 }
@@ -0,0 +1,360 @@
+package period_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/apd"
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+func TestAdd(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		p, q string
+		want string
+	}{
+		{"P1Y2M3DT4H5M6.5S", "P1Y2M3DT4H5M6.5S", "P2Y4M6DT8H10M13S"},
+		{"P1Y", "-P1Y", "P0D"},
+		{"-P1Y2M", "-P3M", "-P1Y5M"},
+		{"PT1H", "-PT1M", "PT59M"},
+		{"PT1H", "-PT0.25S", "PT59M59.75S"},
+		{"-PT1S", "PT0.25S", "-PT0.75S"},
+		{"P1Y", "-P1M", "P11M"},
+		{"P2D", "-P3D", "-P1D"},
+		{"PT0.5S", "PT0.75S", "PT1.25S"},
+		{"P1DT1H", "-PT30M", "P1DT30M"},
+	}
+
+	for i, c := range cases {
+		p, q := period.MustParse(c.p, false), period.MustParse(c.q, false)
+		got, err := p.AddWithOverflowCheck(q)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, c.want))
+		sum := p.Add(q)
+		is.Equal(info(i, sum.String()), info(i, c.want))
+
+		// Adding is commutative
+		sum = q.Add(p)
+		is.Equal(info(i, sum.String()), info(i, c.want))
+	}
+}
+
+func TestSub(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		p, q string
+		want string
+	}{
+		{"P1Y2M3DT4H5M6.5S", "P1Y2M3DT4H5M6.5S", "P0D"},
+		{"P1Y", "P2Y", "-P1Y"},
+		{"-P1Y", "-P2Y", "P1Y"},
+		{"PT1M", "PT0.001S", "PT59.999S"},
+		{"P0D", "PT1.5S", "-PT1.5S"},
+		{"P1Y", "P1M", "P11M"},
+	}
+
+	for i, c := range cases {
+		p, q := period.MustParse(c.p, false), period.MustParse(c.q, false)
+		got, err := p.SubWithOverflowCheck(q)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, c.want))
+		diff := p.Sub(q)
+		is.Equal(info(i, diff.String()), info(i, c.want))
+
+		// Subtracting then adding gives the original value, though perhaps not
+		// in the same fields
+		back := got.Add(q).Sub(p)
+		is.True(back.IsZero())
+	}
+}
+
+func TestAddMixedSigns(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		p, q string
+		want string
+	}{
+		{"P1M", "-P1D", "P1M-1D"},
+		{"P1D", "-PT1H", "P1DT-1H"},
+		{"-P1D", "PT1H", "P-1DT1H"},
+		{"P1Y-2M", "P2M", "P1Y"},
+		{"P1Y-2M", "-P1Y", "-P2M"},
+		{"P1M-1D", "P1D", "P1M"},
+	}
+
+	for i, c := range cases {
+		p, q := period.MustParse(c.p, false), period.MustParse(c.q, false)
+		got, err := p.AddWithOverflowCheck(q)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, c.want))
+	}
+}
+
+func TestAddErrors(t *testing.T) {
+	is := is.New(t)
+
+	// Overflow in the largest field
+	huge := period.NewPeriod(math.MaxInt64, 0, 0, 0, 0, 0)
+	_, err := huge.AddWithOverflowCheck(period.NewPeriod(1, 0, 0, 0, 0, 0))
+	is.True(err != nil)
+
+	// Subtraction has the same checks
+	_, err = huge.SubWithOverflowCheck(period.NewPeriod(-1, 0, 0, 0, 0, 0))
+	is.True(err != nil)
+	negHuge := huge
+	_, err = negHuge.Negate().SubWithOverflowCheck(period.NewPeriod(2, 0, 0, 0, 0, 0))
+	is.True(err != nil)
+
+	// Add and Sub give the zero period on overflow
+	is.True(huge.Add(period.NewPeriod(1, 0, 0, 0, 0, 0)).IsZero())
+	is.True(huge.Sub(period.NewPeriod(-1, 0, 0, 0, 0, 0)).IsZero())
+}
+
+func TestAddOverflowCarries(t *testing.T) {
+	is := is.New(t)
+
+	// 2^63 seconds overflows, so is carried into minutes and hours
+	p := period.NewHMS(0, 0, 1<<62)
+	got, err := p.AddWithOverflowCheck(p)
+	is.NoErr(err)
+	is.Equal(got.String(), "PT2562047788015215H30M8S")
+
+	// 2^63 months overflows, so is carried into years
+	p = period.NewYMD(0, math.MaxInt64, 0)
+	got, err = p.AddWithOverflowCheck(period.NewYMD(0, 1, 0))
+	is.NoErr(err)
+	is.Equal(got.String(), "P768614336404564650Y8M")
+}
+
+func TestNegateAndAbs(t *testing.T) {
+	is := is.New(t)
+
+	p := period.MustParse("PT1.5S", false)
+	is.Equal(p.Negate().String(), "-PT1.5S")
+	abs := p.Abs()
+	is.Equal(abs.String(), "PT1.5S")
+	is.Equal(p.Negate().String(), "PT1.5S")
+
+	zero := period.MustParse("P0D", false)
+	is.Equal(zero.Negate().String(), "P0D")
+	is.True(zero.IsPositive())
+}
+
+func TestAddTo(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	t0 := time.Date(2021, 1, 31, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		t    time.Time
+		p    string
+		want time.Time
+	}{
+		{t0, "P1M", time.Date(2021, 3, 3, 10, 0, 0, 0, time.UTC)},
+		{t0, "-P1M", time.Date(2020, 12, 31, 10, 0, 0, 0, time.UTC)},
+		{t0, "-P2M", time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)},
+		{t0, "P1Y2M3DT4H5M6.5S", time.Date(2022, 4, 3, 14, 5, 6, 500000000, time.UTC)},
+		{t0, "-P1Y2M3DT4H5M6.5S", time.Date(2019, 11, 28, 5, 54, 53, 500000000, time.UTC)},
+		{t0, "PT0.001S", time.Date(2021, 1, 31, 10, 0, 0, 1000000, time.UTC)},
+		{t0, "-PT36H", time.Date(2021, 1, 29, 22, 0, 0, 0, time.UTC)},
+		{t0, "P1.5M", time.Date(2021, 3, 18, 15, 0, 0, 0, time.UTC)},
+		{t0, "P1000000Y", time.Date(1002021, 1, 31, 10, 0, 0, 0, time.UTC)},
+		{t0, "P1M-1D", time.Date(2021, 3, 2, 10, 0, 0, 0, time.UTC)},
+		{t0, "P-1DT1H", time.Date(2021, 1, 30, 11, 0, 0, 0, time.UTC)},
+		{t0, "-P-1DT1H", time.Date(2021, 2, 1, 9, 0, 0, 0, time.UTC)},
+		// A day across the start of daylight saving time keeps the clock time
+		{time.Date(2021, 3, 13, 12, 0, 0, 0, toronto), "P1D", time.Date(2021, 3, 14, 12, 0, 0, 0, toronto)},
+		{time.Date(2021, 3, 13, 12, 0, 0, 0, toronto), "PT24H", time.Date(2021, 3, 14, 13, 0, 0, 0, toronto)},
+	}
+
+	for i, c := range cases {
+		got, precise, err := period.MustParse(c.p, false).AddTo(c.t)
+		is.NoErr(err)
+		is.True(precise)
+		is.Equal(info(i, got.String()), info(i, c.want.String()))
+
+		// Subtracting takes the time back, except where months overflowed
+		back, _, err := period.MustParse(c.p, false).SubFrom(got)
+		is.NoErr(err)
+		if c.t.Day() == back.Day() {
+			is.Equal(info(i, back.String()), info(i, c.t.String()))
+		}
+	}
+
+	// Elapsed time too large for a time.Duration
+	got, _, err := period.NewHMS(0, 0, 10000000000).AddTo(t0)
+	is.NoErr(err)
+	is.Equal(got, t0.Add(5000000000*time.Second).Add(5000000000*time.Second))
+}
+
+func TestAddToWithEndOfMonth(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		t        time.Time
+		p        string
+		overflow time.Time
+		clamp    time.Time
+	}{
+		{time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC), "P1M", time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2020, 1, 31, 0, 0, 0, 0, time.UTC), "P1M", time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC), "P1Y", time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC), "-P1M", time.Date(2021, 3, 3, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC), "-P1M1D", time.Date(2021, 3, 2, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 27, 0, 0, 0, 0, time.UTC)},
+		{time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC), "P1MT1H", time.Date(2021, 3, 3, 1, 0, 0, 0, time.UTC), time.Date(2021, 2, 28, 1, 0, 0, 0, time.UTC)},
+	}
+
+	for i, c := range cases {
+		p := period.MustParse(c.p, false)
+
+		got, _, err := p.AddToWithEndOfMonth(c.t, period.EndOfMonthOverflow)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, c.overflow.String()))
+
+		got, _, err = p.AddToWithEndOfMonth(c.t, period.EndOfMonthClamp)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, c.clamp.String()))
+
+		_, _, err = p.AddToWithEndOfMonth(c.t, period.EndOfMonthError)
+		is.True(err != nil) // day is beyond the end of the month
+
+		// Subtracting the negated period is the same
+		got, _, err = p.Negate().SubFromWithEndOfMonth(c.t, period.EndOfMonthClamp)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, c.clamp.String()))
+	}
+
+	// Days within the month are not affected by the policy
+	got, _, err := period.MustParse("P1M", false).AddToWithEndOfMonth(time.Date(2021, 1, 28, 0, 0, 0, 0, time.UTC), period.EndOfMonthError)
+	is.NoErr(err)
+	is.Equal(got, time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC))
+}
+
+func TestScale(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		period string
+		factor float64
+		want   string
+	}{
+		{"P1Y2M", 2, "P2Y4M"},
+		{"P2D", -1, "-P2D"},
+		{"-P2D", -1, "P2D"},
+		{"-P1Y2M", 2, "-P2Y4M"},
+		{"PT1H30M", -2, "-PT3H"},
+		// each field keeps its own sign
+		{"P1Y-2M", 2, "P1Y8M"},
+		{"P1Y-2M", -2, "-P1Y8M"},
+		{"P1M-1D", 3, "P3M-3D"},
+		{"P1M-1D", -3, "P-3M3D"},
+	}
+
+	for i, c := range cases {
+		p := period.MustParse(c.period, false)
+		got, err := p.ScaleWithOverflowCheck(c.factor)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, c.want))
+		is.Equal(info(i, p.Scale(c.factor).String()), info(i, c.want))
+	}
+
+	_, err := period.NewYMD(math.MaxInt64/2, 0, 0).ScaleWithOverflowCheck(4)
+	is.True(err != nil)
+}
+
+func TestScaleRat(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		p      string
+		factor *big.Rat
+		basis  period.MonthBasis
+		want   string
+	}{
+		{"P1M", big.NewRat(1, 2), period.AverageMonth, "P15DT5H14M33S"},
+		{"P1M", big.NewRat(1, 2), period.ThirtyDayMonth, "P15D"},
+		{"P1M", big.NewRat(1, 2), period.CommonYearMonth, "P15DT5H"},
+		{"P1Y", big.NewRat(1, 2), period.AverageMonth, "P6M"},
+		{"P1Y", big.NewRat(1, 8), period.ThirtyDayMonth, "P1M15D"},
+		{"PT1H", big.NewRat(1, 3), period.AverageMonth, "PT20M"},
+		{"PT1S", big.NewRat(1, 3), period.AverageMonth, "PT0.333333333S"},
+		{"P1W", big.NewRat(1, 2), period.AverageMonth, "P3DT12H"},
+		{"P1Y2M3DT4H5M6S", big.NewRat(2, 1), period.AverageMonth, "P2Y4M6DT8H10M12S"},
+		{"P1Y2M", big.NewRat(-3, 2), period.ThirtyDayMonth, "-P1Y9M"},
+		{"-PT1H", big.NewRat(1, 4), period.AverageMonth, "-PT15M"},
+		{"P1D", big.NewRat(1, 1000000000000), period.AverageMonth, "PT0.000000086S"},
+	}
+
+	for i, c := range cases {
+		got, err := period.MustParse(c.p, false).ScaleRat(c.factor, c.basis)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, c.want))
+	}
+
+	_, err := period.MustParse("P9000000000000000000Y", false).ScaleRat(big.NewRat(2, 1), period.AverageMonth)
+	is.True(err != nil) // overflow
+}
+
+func TestScaleDecimal(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		p      string
+		factor string
+		want   string
+	}{
+		{"P1M", "0.5", "P15D"},
+		{"PT1H", "1.25", "PT1H15M"},
+		{"PT10S", "-0.1", "-PT1S"},
+		{"P1D", "1E+2", "P100D"},
+	}
+
+	for i, c := range cases {
+		factor, _, err := apd.NewFromString(c.factor)
+		is.NoErr(err)
+		got, err := period.MustParse(c.p, false).ScaleDecimal(factor, period.ThirtyDayMonth)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, c.want))
+	}
+
+	nan, _, err := apd.NewFromString("NaN")
+	is.NoErr(err)
+	_, err = period.MustParse("P1D", false).ScaleDecimal(nan, period.AverageMonth)
+	is.True(err != nil)
+}
+
+func TestMultiplyDivide(t *testing.T) {
+	is := is.New(t)
+
+	got, err := period.MustParse("P1Y2M3DT4H5M6.5S", false).Multiply(3)
+	is.NoErr(err)
+	is.Equal(got.String(), "P3Y6M9DT12H15M19.5S")
+
+	got, err = period.MustParse("PT1H", false).Multiply(-2)
+	is.NoErr(err)
+	is.Equal(got.String(), "-PT2H")
+
+	_, err = period.MustParse("P5000000000000000000Y", false).Multiply(2)
+	is.True(err != nil) // overflow
+
+	got, err = period.MustParse("P1D", false).Divide(3, period.AverageMonth)
+	is.NoErr(err)
+	is.Equal(got.String(), "PT8H")
+
+	got, err = period.MustParse("P1Y", false).Divide(24, period.ThirtyDayMonth)
+	is.NoErr(err)
+	is.Equal(got.String(), "P15D")
+
+	_, err = period.MustParse("P1D", false).Divide(0, period.AverageMonth)
+	is.True(err != nil)
+}
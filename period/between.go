@@ -0,0 +1,304 @@
+package period
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"time"
+)
+
+// Unit selects the components of a period to be used by BetweenUnits. Units
+// can be combined, e.g. Month | Day.
+type Unit uint
+
+// Units for BetweenUnits
+const (
+	Year Unit = 1 << iota
+	Month
+	Day
+	Hour
+	Minute
+	Second
+	Nanosecond
+)
+
+// AllUnits selects every component of a period.
+const AllUnits = Year | Month | Day | Hour | Minute | Second | Nanosecond
+
+// Between gives the calendar-exact period from t1 to t2, using all of years,
+// months, days, hours, minutes, seconds and nanoseconds. The result is
+// negative if t2 is before t1.
+//
+// The calculation is done in the location of t1 and follows the rules of
+// time.AddDate, so that t1.AddDate(years, months, days) (with each part
+// negated for a negative period) followed by adding the clock part gives t2
+// exactly. Months of differing lengths, leap years and daylight saving changes
+// are all accounted for.
+//
+// Note that, as with time.AddDate, counting months from the end of a month can
+// overflow into the next; e.g. from 31st January to 1st March is 29 days in
+// 2021, not 1 month and 1 day.
+func Between(t1, t2 time.Time) Period {
+	return BetweenUnits(t1, t2, AllUnits)
+}
+
+// BetweenUnits gives the period from t1 to t2 using only the selected units,
+// e.g. Day for a number of days or Month | Day for months and days. Any part
+// of the span smaller than the smallest selected unit is dropped, so the
+// result never goes beyond t2. If no units are given, all are used.
+//
+// When Year is selected without Month, only whole years are counted. When no
+// clock units are selected, the hours, minutes and seconds are dropped. When
+// Day is not selected, whole days are given as elapsed hours, minutes or
+// seconds instead.
+func BetweenUnits(t1, t2 time.Time, units Unit) (p Period) {
+	if units == 0 {
+		units = AllUnits
+	}
+
+	t2 = t2.In(t1.Location())
+
+	sign := 1
+	if t2.Before(t1) {
+		sign = -1
+	}
+
+	// past reports whether t has gone beyond t2 when moving away from t1
+	past := func(t time.Time) bool {
+		if sign > 0 {
+			return t.After(t2)
+		}
+		return t.Before(t2)
+	}
+
+	// Count months by stepping from an estimate based on the calendar dates.
+	months := 0
+	if units&(Year|Month) != 0 {
+		step := 1
+		if units&Month == 0 {
+			step = 12
+		}
+
+		y1, m1, _ := t1.Date()
+		y2, m2, _ := t2.Date()
+		months = sign * ((y2-y1)*12 + int(m2-m1))
+		months -= months % step
+		if months < 0 {
+			months = 0
+		}
+
+		for months > 0 && past(t1.AddDate(0, sign*months, 0)) {
+			months -= step
+		}
+		for !past(t1.AddDate(0, sign*(months+step), 0)) {
+			months += step
+		}
+	}
+
+	days := 0
+	if units&Day != 0 {
+		days = sign * (civilDay(t2) - civilDay(t1.AddDate(0, sign*months, 0)))
+		if days < 0 {
+			days = 0
+		}
+
+		for days > 0 && past(t1.AddDate(0, sign*months, sign*days)) {
+			days--
+		}
+		for !past(t1.AddDate(0, sign*months, sign*(days+1))) {
+			days++
+		}
+	}
+
+	anchor := t1.AddDate(0, sign*months, sign*days)
+
+	// The remainder is at least zero because the anchor is never past t2.
+	seconds := int64(sign) * (t2.Unix() - anchor.Unix())
+	nanoseconds := int64(sign) * int64(t2.Nanosecond()-anchor.Nanosecond())
+	if nanoseconds < 0 {
+		nanoseconds += int64(time.Second)
+		seconds--
+	}
+
+	switch {
+	case units&Year != 0 && units&Month != 0:
+		p.years, p.months = int64(months/12), int64(months%12)
+	case units&Year != 0:
+		p.years = int64(months / 12)
+	default:
+		p.months = int64(months)
+	}
+	p.days = int64(days)
+
+	clock := []struct {
+		unit  Unit
+		size  int64
+		field *int64
+	}{
+		{Hour, 3600, &p.hours},
+		{Minute, 60, &p.minutes},
+		{Second, 1, &p.seconds},
+	}
+	for _, c := range clock {
+		if units&c.unit != 0 {
+			*c.field = seconds / c.size
+			seconds %= c.size
+		}
+	}
+	if units&Nanosecond != 0 {
+		p.nanoseconds = seconds*int64(time.Second) + nanoseconds
+	}
+
+	p.negative = sign < 0 && !p.IsZero()
+
+	return p
+}
+
+// civilDay gives the number of days from the Unix epoch to the calendar date
+// of t, ignoring the clock and location.
+func civilDay(t time.Time) int {
+	y, m, d := t.Date()
+	return int(time.Date(y, m, d, 0, 0, 0, 0, time.UTC).Unix() / 86400)
+}
+
+// FitBetween gives the number of whole periods that fit from t1 to t2, along
+// with the period left over, as with FitBetweenWithEndOfMonth using
+// EndOfMonthOverflow.
+func (p Period) FitBetween(t1, t2 time.Time) (int64, Period, error) {
+	return p.FitBetweenWithEndOfMonth(t1, t2, EndOfMonthOverflow)
+}
+
+// FitBetweenWithEndOfMonth gives the number of whole periods that fit from t1
+// to t2, along with the period left over. The nth period ends at t1 plus n
+// times the period, added with AddToWithEndOfMonth, so that months are
+// counted from t1 and do not drift; e.g. with EndOfMonthClamp five P1M fit
+// from 31st January 2021 to 15th July 2021, ending on 30th June, with P15D
+// left over. The remainder is given by Between, so adding it to the end of the
+// last period gives t2 exactly.
+//
+// If t2 is before t1, the period must be negative. An error is returned if
+// the period is zero or moves away from t2, or if a time overflows.
+func (p Period) FitBetweenWithEndOfMonth(t1, t2 time.Time, eom EndOfMonth) (int64, Period, error) {
+	if t1.Equal(t2) {
+		return 0, Period{}, nil
+	}
+
+	once, _, err := p.AddToWithEndOfMonth(t1, eom)
+	if err != nil {
+		return 0, Period{}, err
+	}
+	forward := t2.After(t1)
+	if once.Equal(t1) || once.After(t1) != forward {
+		return 0, Period{}, errors.New("period: the period does not move from t1 towards t2")
+	}
+
+	// at gives the end of n periods, or false if it cannot be found
+	at := func(n int64) (time.Time, bool) {
+		multiple, err := p.Multiply(n)
+		if err != nil {
+			return time.Time{}, false
+		}
+		t, _, err := multiple.AddToWithEndOfMonth(t1, eom)
+		return t, err == nil
+	}
+	past := func(n int64) bool {
+		t, ok := at(n)
+		if !ok {
+			return true
+		}
+		if forward {
+			return t.After(t2)
+		}
+		return t.Before(t2)
+	}
+
+	// Estimate from the elapsed times, then step to the exact count
+	estimate, _ := new(big.Rat).SetFrac(elapsedNanoseconds(t1, t2), elapsedNanoseconds(t1, once)).Float64()
+	n := int64(estimate)
+	if n < 0 || estimate >= math.MaxInt64 {
+		n = 0
+	}
+	for n > 0 && past(n) {
+		n--
+	}
+	for !past(n + 1) {
+		n++
+	}
+
+	end, _ := at(n)
+
+	return n, Between(end, t2), nil
+}
+
+// Ratio gives the length of the period relative to another, as an exact
+// fraction. Both are measured as elapsed time from the reference time, so P1M
+// relative to P1W is 4 from 1st February 2021 but 31/7 from 1st March.
+//
+// An error is returned if that period has no length from the reference time or
+// either period cannot be added to it.
+func (p Period) Ratio(that Period, ref time.Time) (*big.Rat, error) {
+	end, _, err := p.AddTo(ref)
+	if err != nil {
+		return nil, err
+	}
+	thatEnd, _, err := that.AddTo(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	denominator := elapsedNanoseconds(ref, thatEnd)
+	if denominator.Sign() == 0 {
+		return nil, errors.New("period: ratio to a period of no length")
+	}
+
+	return new(big.Rat).SetFrac(elapsedNanoseconds(ref, end), denominator), nil
+}
+
+// Split divides the time from t1 to t2 into n parts of equal elapsed time,
+// giving each part as a period from Between. Adding each period in turn with
+// AddTo, starting from t1, gives t2 exactly. Where the elapsed time does not
+// divide exactly, the parts differ by at most a nanosecond.
+//
+// As the parts are equal in elapsed time, their calendar periods can differ;
+// e.g. in Toronto, where the clocks went forward on 14th March 2021, 13th to
+// 15th March splits in two as PT23H30M and P1DT30M. An error is returned if n
+// is less than one.
+func Split(t1, t2 time.Time, n int) ([]Period, error) {
+	if n < 1 {
+		return nil, errors.New("period: cannot split into fewer than one part")
+	}
+
+	total := elapsedNanoseconds(t1, t2)
+	parts := make([]Period, n)
+	start := t1
+	for i := 1; i <= n; i++ {
+		// offset is total * i / n, truncated towards zero
+		offset := new(big.Int).Mul(total, big.NewInt(int64(i)))
+		offset.Quo(offset, big.NewInt(int64(n)))
+
+		end := t2
+		if i < n {
+			end = addNanoseconds(t1, offset)
+		}
+		parts[i-1] = Between(start, end)
+		start = end
+	}
+
+	return parts, nil
+}
+
+// elapsedNanoseconds gives the nanoseconds from start to end, which can be
+// more than a time.Duration holds.
+func elapsedNanoseconds(start, end time.Time) *big.Int {
+	elapsed := big.NewInt(end.Unix() - start.Unix())
+	elapsed.Mul(elapsed, big.NewInt(int64(time.Second)))
+
+	return elapsed.Add(elapsed, big.NewInt(int64(end.Nanosecond()-start.Nanosecond())))
+}
+
+// addNanoseconds adds nanoseconds to t, which must give a valid time.
+func addNanoseconds(t time.Time, nanoseconds *big.Int) time.Time {
+	seconds, remainder := new(big.Int).QuoRem(nanoseconds, big.NewInt(int64(time.Second)), new(big.Int))
+
+	return time.Unix(t.Unix()+seconds.Int64(), int64(t.Nanosecond())+remainder.Int64()).In(t.Location())
+}
@@ -0,0 +1,216 @@
+package period_test
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+// addExact adds a period to a time with time.AddDate so that the result of
+// Between can be checked independently of the period arithmetic.
+func addExact(t time.Time, p period.Period) time.Time {
+	clock := time.Duration(p.Hours())*time.Hour +
+		time.Duration(p.Minutes())*time.Minute +
+		time.Duration(p.Seconds())*time.Second +
+		time.Duration(p.Nanoseconds())
+
+	return t.AddDate(int(p.Years()), int(p.Months()), int(p.Days())).Add(clock)
+}
+
+func TestBetween(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	cases := []struct {
+		t1, t2 time.Time
+		want   string
+	}{
+		{time.Date(2021, 1, 15, 10, 0, 0, 0, time.UTC), time.Date(2022, 3, 20, 12, 30, 15, 500000000, time.UTC), "P1Y2M5DT2H30M15.5S"},
+		{time.Date(2022, 3, 20, 12, 30, 15, 500000000, time.UTC), time.Date(2021, 1, 15, 10, 0, 0, 0, time.UTC), "-P1Y2M5DT2H30M15.5S"},
+		{time.Date(2021, 1, 15, 10, 0, 0, 0, time.UTC), time.Date(2021, 1, 15, 10, 0, 0, 0, time.UTC), "P0D"},
+		{time.Date(2021, 1, 15, 10, 0, 0, 0, time.UTC), time.Date(2021, 1, 15, 10, 0, 0, 1, time.UTC), "PT0.000000001S"},
+		{time.Date(2021, 1, 15, 23, 0, 0, 0, time.UTC), time.Date(2021, 1, 16, 1, 0, 0, 0, time.UTC), "PT2H"},
+		{time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC), "P28D"},
+		{time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC), "P29D"},
+		{time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC), "P2M"},
+		{time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC), "P11M30D"},
+		{time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), "P4Y"},
+		{time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2019, 12, 31, 23, 0, 0, 0, time.UTC), "-PT1H"},
+		// A day across the start of daylight saving time has 23 hours
+		{time.Date(2021, 3, 13, 12, 0, 0, 0, toronto), time.Date(2021, 3, 14, 12, 0, 0, 0, toronto), "P1D"},
+		{time.Date(2021, 3, 13, 12, 0, 0, 0, toronto), time.Date(2021, 3, 14, 11, 0, 0, 0, toronto), "PT22H"},
+		// The location of t1 is used
+		{time.Date(2021, 3, 13, 12, 0, 0, 0, toronto), time.Date(2021, 3, 14, 16, 0, 0, 0, time.UTC), "P1D"},
+	}
+
+	for i, c := range cases {
+		p := period.Between(c.t1, c.t2)
+		is.Equal(info(i, p.String()), info(i, c.want))
+		is.True(addExact(c.t1, p).Equal(c.t2)) // t1 plus the period should be t2
+	}
+}
+
+func TestBetweenUnits(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	t1 := time.Date(2021, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		t1, t2 time.Time
+		units  period.Unit
+		want   string
+	}{
+		{t1, time.Date(2021, 3, 20, 12, 0, 0, 0, time.UTC), period.Day, "P64D"},
+		{t1, time.Date(2021, 3, 20, 12, 0, 0, 0, time.UTC), period.Month | period.Day, "P2M5D"},
+		{t1, time.Date(2021, 3, 20, 12, 0, 0, 0, time.UTC), period.Day | period.Hour, "P64DT2H"},
+		{t1, time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC), period.Month, "P26M"},
+		{t1, time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC), period.Year, "P2Y"},
+		{t1, time.Date(2023, 1, 15, 9, 0, 0, 0, time.UTC), period.Year, "P1Y"},
+		{t1, time.Date(2023, 3, 20, 12, 0, 0, 0, time.UTC), period.Year | period.Day, "P2Y64D"},
+		{t1, time.Date(2021, 1, 17, 11, 30, 0, 0, time.UTC), period.Hour | period.Minute, "PT49H30M"},
+		{t1, time.Date(2021, 1, 17, 11, 30, 0, 0, time.UTC), period.Minute, "PT2970M"},
+		{t1, time.Date(2021, 1, 15, 10, 0, 1, 500, time.UTC), period.Nanosecond, "PT1.0000005S"},
+		{t1, time.Date(2021, 1, 10, 9, 0, 0, 0, time.UTC), period.Day, "-P5D"},
+		{t1, time.Date(2021, 1, 15, 11, 0, 0, 0, time.UTC), period.Day, "P0D"},
+		{t1, time.Date(2022, 3, 20, 12, 30, 15, 0, time.UTC), 0, "P1Y2M5DT2H30M15S"},
+		{time.Date(2021, 3, 13, 12, 0, 0, 0, toronto), time.Date(2021, 3, 14, 12, 0, 0, 0, toronto), period.Hour, "PT23H"},
+	}
+
+	for i, c := range cases {
+		p := period.BetweenUnits(c.t1, c.t2, c.units)
+		is.Equal(info(i, p.String()), info(i, c.want))
+	}
+}
+
+// TestBetweenRoundTrip checks that adding the period from Between to t1
+// always gives t2 exactly.
+func TestBetweenRoundTrip(t *testing.T) {
+	is := is.New(t)
+
+	var locations []*time.Location
+	for _, name := range []string{"UTC", "America/Toronto", "Europe/London", "Australia/Lord_Howe", "Asia/Kolkata"} {
+		loc, err := time.LoadLocation(name)
+		is.NoErr(err)
+		locations = append(locations, loc)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+	random := func() time.Time {
+		loc := locations[rnd.Intn(len(locations))]
+		return time.Unix(rnd.Int63n(4000000000)-1000000000, rnd.Int63n(int64(time.Second))).In(loc)
+	}
+
+	for i := 0; i < 5000; i++ {
+		t1, t2 := random(), random()
+		p := period.Between(t1, t2)
+		is.True(addExact(t1, p).Equal(t2)) // t1 plus the period should be t2
+		is.Equal(info(i, p.IsNegative()), info(i, t2.Before(t1)))
+
+		got, _, err := p.AddTo(t1)
+		is.NoErr(err)
+		is.True(got.Equal(t2)) // AddTo should agree with Between
+	}
+}
+
+func TestFitBetween(t *testing.T) {
+	is := is.New(t)
+
+	jan31 := time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC)
+	jul15 := time.Date(2021, 7, 15, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		p         string
+		t1, t2    time.Time
+		eom       period.EndOfMonth
+		n         int64
+		remainder string
+	}{
+		{"P1M", jan31, jul15, period.EndOfMonthClamp, 5, "P15D"},
+		{"P1M", jan31, jul15, period.EndOfMonthOverflow, 5, "P14D"},
+		{"P1W", jan31, jul15, period.EndOfMonthOverflow, 23, "P4D"},
+		{"PT1H", jan31, jan31.Add(150 * time.Minute), period.EndOfMonthOverflow, 2, "PT30M"},
+		{"-P1M", jul15, jan31, period.EndOfMonthOverflow, 5, "-P15D"},
+		{"P1Y", jan31, jul15, period.EndOfMonthOverflow, 0, "P5M14D"},
+		{"P1D", jan31, jan31, period.EndOfMonthOverflow, 0, "P0D"},
+		{"PT1S", jan31, jan31.AddDate(1000, 0, 0), period.EndOfMonthOverflow, 31556908800, "P0D"},
+	}
+
+	for i, c := range cases {
+		n, remainder, err := period.MustParse(c.p, false).FitBetweenWithEndOfMonth(c.t1, c.t2, c.eom)
+		is.NoErr(err)
+		is.Equal(info(i, n), info(i, c.n))
+		is.Equal(info(i, remainder.String()), info(i, c.remainder))
+	}
+
+	_, _, err := period.MustParse("-P1D", false).FitBetween(jan31, jul15)
+	is.True(err != nil) // moves away from t2
+	_, _, err = period.MustParse("P0D", false).FitBetween(jan31, jul15)
+	is.True(err != nil) // no length
+}
+
+func TestRatio(t *testing.T) {
+	is := is.New(t)
+
+	feb := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+	month, week := period.MustParse("P1M", false), period.MustParse("P1W", false)
+
+	r, err := month.Ratio(week, feb)
+	is.NoErr(err)
+	is.Equal(r.Cmp(big.NewRat(4, 1)), 0)
+
+	r, err = month.Ratio(week, mar)
+	is.NoErr(err)
+	is.Equal(r.Cmp(big.NewRat(31, 7)), 0)
+
+	r, err = period.MustParse("-PT90M", false).Ratio(period.MustParse("PT1H", false), feb)
+	is.NoErr(err)
+	is.Equal(r.Cmp(big.NewRat(-3, 2)), 0)
+
+	_, err = month.Ratio(period.Period{}, feb)
+	is.True(err != nil)
+}
+
+func TestSplit(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	cases := []struct {
+		t1, t2 time.Time
+		n      int
+		want   []string
+	}{
+		{time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), 3, []string{"P1D", "P1D", "P1D"}},
+		{time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 1, 0, 0, 0, 2, time.UTC), 3, []string{"P0D", "PT0.000000001S", "PT0.000000001S"}},
+		{time.Date(2021, 3, 13, 0, 0, 0, 0, toronto), time.Date(2021, 3, 15, 0, 0, 0, 0, toronto), 2, []string{"PT23H30M", "P1DT30M"}},
+		{time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC), time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), 2, []string{"-P1DT12H", "-P1DT12H"}},
+	}
+
+	for i, c := range cases {
+		parts, err := period.Split(c.t1, c.t2, c.n)
+		is.NoErr(err)
+		is.Equal(len(parts), c.n)
+
+		at := c.t1
+		for j, p := range parts {
+			is.Equal(info(i, j, p.String()), info(i, j, c.want[j]))
+			at, _, err = p.AddTo(at)
+			is.NoErr(err)
+		}
+		is.True(at.Equal(c.t2)) // parts add up to the whole
+	}
+
+	_, err = period.Split(time.Now(), time.Now(), 0)
+	is.True(err != nil)
+}
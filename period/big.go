@@ -0,0 +1,454 @@
+package period
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/apd"
+)
+
+// BigPeriod holds a period with arbitrary precision decimal components, for
+// periods beyond the range or precision of Period, such as billions of years
+// to the nanosecond. Arithmetic on a BigPeriod is exact at any magnitude.
+//
+// Each component has its own sign and can have a fraction, e.g. "P1.5Y" keeps
+// one and a half years rather than approximating the half year in smaller
+// units. The zero value is the zero period.
+type BigPeriod struct {
+	// values are years, months, weeks, days, hours, minutes and seconds, where
+	// nil is zero. Values are never changed once set, so they can be shared
+	// between copies.
+	values [7]*apd.Decimal
+}
+
+// Indexes of the BigPeriod values.
+const (
+	bigYears = iota
+	bigMonths
+	bigWeeks
+	bigDays
+	bigHours
+	bigMinutes
+	bigSeconds
+)
+
+// bigDesignators are the designators of each BigPeriod value.
+var bigDesignators = [7]byte{
+	yearChar, minuteMonthChar, weekChar, dayChar, hourChar, minuteMonthChar, secondChar,
+}
+
+var bigZero = apd.New(0, 0)
+
+// ParseBig parses a period using ISO-8601 rules, as with Parse, into a
+// BigPeriod. Components can be of any size and the last can have a fraction of
+// any precision, which is kept as it is.
+//
+// As with Parse, a plus or minus sign can precede the period and each
+// component can have its own sign.
+func ParseBig(period string) (BigPeriod, error) {
+	fail := func(reason string) (BigPeriod, error) {
+		return BigPeriod{}, fmt.Errorf("period.ParseBig: %s %s", period, reason)
+	}
+
+	s := strings.ToUpper(period)
+	negative := false
+	if s != "" && (s[0] == negativeChar || s[0] == plusChar) {
+		negative = s[0] == negativeChar
+		s = s[1:]
+	}
+	if s == "" || s[0] != periodChar {
+		return fail("does not start with P")
+	}
+	s = s[1:]
+	if s == "" {
+		return fail("has no components")
+	}
+
+	var p BigPeriod
+	if s == "0" {
+		return p, nil
+	}
+
+	next := bigYears // the smallest value that can come next
+	isTime, hasFraction := false, false
+	for s != "" {
+		if s[0] == timeChar {
+			if isTime || len(s) == 1 {
+				return fail("has a misplaced T")
+			}
+			isTime = true
+			next = bigHours
+			s = s[1:]
+			continue
+		}
+		if hasFraction {
+			return fail("has a fraction that is not in the last component")
+		}
+
+		// The number runs up to the designator
+		end := strings.IndexAny(s, "YMWDHS")
+		if end < 0 {
+			return fail("has a number without a designator")
+		}
+		number := strings.Replace(s[:end], string(commaChar), string(dotChar), 1)
+		designator := s[end]
+		s = s[end+1:]
+
+		index := -1
+		for i := next; i < len(bigDesignators); i++ {
+			if bigDesignators[i] == designator && (i >= bigHours) == isTime {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			return fail("has components out of order or in the wrong part")
+		}
+		next = index + 1
+
+		value, err := parseBigNumber(number)
+		if err != nil {
+			return fail(err.Error())
+		}
+		hasFraction = strings.ContainsRune(number, dotChar)
+		if negative {
+			value.Neg(value)
+		}
+		p.values[index] = value
+	}
+
+	return p, nil
+}
+
+// parseBigNumber parses an optionally signed decimal number with at least one
+// digit.
+func parseBigNumber(number string) (*apd.Decimal, error) {
+	digits := number
+	if digits != "" && (digits[0] == negativeChar || digits[0] == plusChar) {
+		digits = digits[1:]
+	}
+
+	point := strings.IndexByte(digits, dotChar)
+	whole, fraction := digits, ""
+	if point >= 0 {
+		whole, fraction = digits[:point], digits[point+1:]
+	}
+	if whole == "" && fraction == "" {
+		return nil, errors.New("has a component without digits")
+	}
+	for _, r := range whole + fraction {
+		if r < '0' || r > '9' {
+			return nil, errors.New("has a component that is not a number")
+		}
+	}
+
+	if fraction != "" {
+		whole = "0" + whole + "." + fraction
+	}
+	value, _, err := apd.NewFromString(whole)
+	if err != nil {
+		return nil, err
+	}
+	if number[0] == negativeChar {
+		value.Neg(value)
+	}
+
+	return value, nil
+}
+
+// MustParseBig is as with ParseBig except that it panics if the string cannot
+// be parsed. This is intended for setup code; don't use it for user inputs.
+func MustParseBig(value string) BigPeriod {
+	p, err := ParseBig(value)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Big converts the period to a BigPeriod, which is always exact. The
+// nanoseconds become a fraction of the seconds.
+func (p Period) Big() BigPeriod {
+	fields := p.signedFields()
+
+	var big BigPeriod
+	for i, v := range fields[:bigSeconds] {
+		big.values[i] = apd.New(v, 0)
+	}
+	// Seconds and nanoseconds are both int64, so the sum cannot fail
+	seconds := apd.New(fields[6], 0)
+	bigContext().Add(seconds, seconds, apd.New(fields[7], -9))
+	big.values[bigSeconds] = seconds
+
+	return big
+}
+
+// Period converts the big period to a Period. An error is returned if any
+// component does not fit an int64, if any component other than the seconds
+// has a fraction, or if the seconds have a fraction smaller than a nanosecond.
+// Normalise the big period first to carry fractions into smaller components.
+func (p BigPeriod) Period() (Period, error) {
+	var fields [8]int64
+	for i := range p.values[:bigSeconds] {
+		v, err := p.value(i).Int64()
+		if err != nil {
+			return Period{}, fmt.Errorf("period.BigPeriod.Period: %s cannot be held in a Period", p.String())
+		}
+		fields[i] = v
+	}
+
+	// Split the seconds into whole seconds and nanoseconds
+	ctx := bigContext()
+	nanoseconds := new(apd.Decimal)
+	ctx.Mul(nanoseconds, p.value(bigSeconds), apd.New(int64(time.Second), 0))
+	seconds := new(apd.Decimal)
+	remainder := new(apd.Decimal)
+	if _, err := divContext(nanoseconds).QuoInteger(seconds, nanoseconds, apd.New(int64(time.Second), 0)); err != nil {
+		return Period{}, err
+	}
+	if _, err := divContext(nanoseconds).Rem(remainder, nanoseconds, apd.New(int64(time.Second), 0)); err != nil {
+		return Period{}, err
+	}
+
+	var err1, err2 error
+	fields[6], err1 = seconds.Int64()
+	fields[7], err2 = remainder.Int64()
+	if err1 != nil || err2 != nil {
+		return Period{}, fmt.Errorf("period.BigPeriod.Period: %s cannot be held in a Period", p.String())
+	}
+
+	return fromSignedFields(fields)
+}
+
+// value gives a value, which is zero if not set.
+func (p BigPeriod) value(i int) *apd.Decimal {
+	if p.values[i] == nil {
+		return bigZero
+	}
+	return p.values[i]
+}
+
+// Years gets the years of the period with proper sign.
+func (p BigPeriod) Years() *apd.Decimal {
+	return new(apd.Decimal).Set(p.value(bigYears))
+}
+
+// Months gets the months of the period with proper sign.
+func (p BigPeriod) Months() *apd.Decimal {
+	return new(apd.Decimal).Set(p.value(bigMonths))
+}
+
+// Weeks gets the weeks of the period with proper sign.
+func (p BigPeriod) Weeks() *apd.Decimal {
+	return new(apd.Decimal).Set(p.value(bigWeeks))
+}
+
+// Days gets the days of the period with proper sign.
+func (p BigPeriod) Days() *apd.Decimal {
+	return new(apd.Decimal).Set(p.value(bigDays))
+}
+
+// Hours gets the hours of the period with proper sign.
+func (p BigPeriod) Hours() *apd.Decimal {
+	return new(apd.Decimal).Set(p.value(bigHours))
+}
+
+// Minutes gets the minutes of the period with proper sign.
+func (p BigPeriod) Minutes() *apd.Decimal {
+	return new(apd.Decimal).Set(p.value(bigMinutes))
+}
+
+// Seconds gets the seconds of the period with proper sign, including any
+// fraction of a second.
+func (p BigPeriod) Seconds() *apd.Decimal {
+	return new(apd.Decimal).Set(p.value(bigSeconds))
+}
+
+// IsZero reports whether every component is zero.
+func (p BigPeriod) IsZero() bool {
+	for i := range p.values {
+		if p.value(i).Sign() != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsNegative reports whether the period is negative, which is when no
+// component is positive and at least one is negative.
+func (p BigPeriod) IsNegative() bool {
+	negative := false
+	for i := range p.values {
+		switch p.value(i).Sign() {
+		case 1:
+			return false
+		case -1:
+			negative = true
+		}
+	}
+	return negative
+}
+
+// Negate changes the sign of every component.
+func (p BigPeriod) Negate() BigPeriod {
+	var result BigPeriod
+	for i := range p.values {
+		result.values[i] = new(apd.Decimal).Neg(p.value(i))
+	}
+	return result
+}
+
+// Abs converts a negative period to a positive period. A period with
+// components of differing signs is unchanged.
+func (p BigPeriod) Abs() BigPeriod {
+	if p.IsNegative() {
+		return p.Negate()
+	}
+	return p
+}
+
+// Add adds two periods together exactly, component by component. No values
+// are carried between components; use Normalise for that.
+func (p BigPeriod) Add(that BigPeriod) BigPeriod {
+	var result BigPeriod
+	for i := range p.values {
+		sum := new(apd.Decimal)
+		// With rounding disabled the sum is exact and, as its exponent is that
+		// of one of its terms, it cannot fail
+		bigContext().Add(sum, p.value(i), that.value(i))
+		result.values[i] = sum
+	}
+	return result
+}
+
+// Sub subtracts one period from another exactly, as with Add.
+func (p BigPeriod) Sub(that BigPeriod) BigPeriod {
+	return p.Add(that.Negate())
+}
+
+// Scale multiplies every component of the period exactly by a factor, which
+// can have a fraction. An error is returned only if a result is beyond the
+// range of the decimal exponent.
+func (p BigPeriod) Scale(factor *apd.Decimal) (BigPeriod, error) {
+	var result BigPeriod
+	for i := range p.values {
+		product := new(apd.Decimal)
+		if _, err := bigContext().Mul(product, p.value(i), factor); err != nil {
+			return BigPeriod{}, fmt.Errorf("period.BigPeriod.Scale: %v", err)
+		}
+		result.values[i] = product
+	}
+	return result, nil
+}
+
+// Normalise carries values exactly between components that have a fixed
+// ratio, as with Period.Normalise. So there are fewer than 12 months, 60
+// minutes and 60 seconds, and fractions are carried into smaller components,
+// e.g. "P1.5Y" becomes "P1Y6M" and "PT1.5H" becomes "PT1H30M". Within each
+// group of components the values end up with the same sign.
+//
+// When precise is false, whole days are also made from hours, on the basis that
+// days are 24 hours long. Weeks are left as they are, and a fraction of a month
+// is not carried into days as months have no fixed length.
+func (p *BigPeriod) Normalise(precise bool) *BigPeriod {
+	p.carry(bigYears, bigMonths, 12)
+	if precise {
+		p.carry(bigHours, bigSeconds, 60, 60)
+	} else {
+		p.carry(bigDays, bigSeconds, 24, 60, 60)
+	}
+	return p
+}
+
+// carry carries values between the components from and to inclusive, in which
+// ratios[i] is the number of units of component from+i+1 in component from+i.
+func (p *BigPeriod) carry(from, to int, ratios ...int64) {
+	ctx := bigContext()
+
+	// Get the exact total in the smallest unit
+	total := new(apd.Decimal)
+	for i := from; i <= to; i++ {
+		if i > from {
+			ctx.Mul(total, total, apd.New(ratios[i-from-1], 0))
+		}
+		ctx.Add(total, total, p.value(i))
+	}
+
+	// The smallest unit keeps any fraction, and the larger units are whole
+	for i := to; i > from; i-- {
+		ratio := apd.New(ratios[i-from-1], 0)
+		remainder := new(apd.Decimal)
+		quotient := new(apd.Decimal)
+		if _, err := divContext(total).Rem(remainder, total, ratio); err != nil {
+			return
+		}
+		if _, err := divContext(total).QuoInteger(quotient, total, ratio); err != nil {
+			return
+		}
+		p.values[i] = remainder
+		total = quotient
+	}
+	p.values[from] = total
+}
+
+// String formats the period using ISO-8601 designators, such as
+// "P15000000000Y2M3DT4H5M6.000000000001S". A period with no positive components
+// is written with a leading minus sign.
+func (p BigPeriod) String() string {
+	if p.IsZero() {
+		return "P0D"
+	}
+
+	var b strings.Builder
+	if p.IsNegative() {
+		b.WriteByte(negativeChar)
+		p = p.Negate()
+	}
+	b.WriteByte(periodChar)
+
+	for i := range p.values {
+		v := p.value(i)
+		if i == bigHours && !p.hasTime() {
+			break
+		}
+		if i == bigHours {
+			b.WriteByte(timeChar)
+		}
+		if v.Sign() == 0 {
+			continue
+		}
+		reduced := new(apd.Decimal)
+		reduced.Reduce(v)
+		b.WriteString(reduced.Text('f'))
+		b.WriteByte(bigDesignators[i])
+	}
+
+	return b.String()
+}
+
+// hasTime reports whether any of the hours, minutes and seconds are non-zero.
+func (p BigPeriod) hasTime() bool {
+	for i := bigHours; i <= bigSeconds; i++ {
+		if p.value(i).Sign() != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// bigContext gives a context for exact addition and multiplication, with
+// rounding disabled.
+func bigContext() *apd.Context {
+	ctx := apd.BaseContext
+	return &ctx
+}
+
+// divContext gives a context with enough precision for the integer quotient
+// and remainder of x, so that QuoInteger and Rem are exact.
+func divContext(x *apd.Decimal) *apd.Context {
+	digits := x.NumDigits() + int64(math.Abs(float64(x.Exponent))) + 1
+	return apd.BaseContext.WithPrecision(uint32(digits))
+}
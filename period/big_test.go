@@ -0,0 +1,192 @@
+package period_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/cockroachdb/apd"
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+func TestParseBig(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"P0", "P0D"},
+		{"P0D", "P0D"},
+		{"PT0S", "P0D"},
+		{"P100Y", "P100Y"},
+		{"p1d", "P1D"},
+		{"P1Y2M3W4DT5H6M7S", "P1Y2M3W4DT5H6M7S"},
+		{"P15000000000Y2M3DT4H5M6.000000000001S", "P15000000000Y2M3DT4H5M6.000000000001S"},
+		{"P1000000000000000000000000000000D", "P1000000000000000000000000000000D"},
+		{"P1.5Y", "P1.5Y"},
+		{"PT0,5S", "PT0.5S"},
+		{"P.5D", "P0.5D"},
+		{"PT1.500S", "PT1.5S"},
+		{"-P1Y2M", "-P1Y2M"},
+		{"+P1Y2M", "P1Y2M"},
+		{"P1Y-2M", "P1Y-2M"},
+		{"-P-1D", "P1D"},
+		{"P-1Y-2M", "-P1Y2M"},
+	}
+
+	for i, test := range tests {
+		p, err := period.ParseBig(test.input)
+		is.NoErr(err)
+		is.Equal(info(i, p.String()), info(i, test.want))
+
+		// The string form parses back to the same period
+		p2, err := period.ParseBig(p.String())
+		is.NoErr(err)
+		is.Equal(info(i, p2.String()), info(i, p.String()))
+	}
+
+	bad := []string{
+		"",
+		"P",
+		"PT",
+		"1Y",
+		"P1",
+		"P1Y1Y",
+		"P1D1Y",
+		"P1H",
+		"PT1D",
+		"P1.5YT1H",
+		"P1T",
+		"PxY",
+		"P.Y",
+	}
+
+	for i, test := range bad {
+		_, err := period.ParseBig(test)
+		is.True(info(i, err) != info(i, nil)) // should fail
+	}
+}
+
+func TestBigNormalise(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input     string
+		precise   string
+		imprecise string
+	}{
+		{"P1.5Y", "P1Y6M", "P1Y6M"},
+		{"P14M", "P1Y2M", "P1Y2M"},
+		{"P1Y-2M", "P10M", "P10M"},
+		{"P1.5M", "P1.5M", "P1.5M"},
+		{"PT1.5H", "PT1H30M", "PT1H30M"},
+		{"PT90M", "PT1H30M", "PT1H30M"},
+		{"PT25H", "PT25H", "P1DT1H"},
+		{"P1.5D", "P1.5D", "P1DT12H"},
+		{"P1DT-1H", "P1DT-1H", "PT23H"},
+		{"P2.5W", "P2.5W", "P2.5W"},
+		{"PT59M60.000000000001S", "PT1H0.000000000001S", "PT1H0.000000000001S"},
+		{"P15000000000.25Y", "P15000000000Y3M", "P15000000000Y3M"},
+		{"-PT3601.5S", "-PT1H1.5S", "-PT1H1.5S"},
+	}
+
+	for i, test := range tests {
+		p := period.MustParseBig(test.input)
+		is.Equal(info(i, p.Normalise(true).String()), info(i, test.precise))
+
+		p = period.MustParseBig(test.input)
+		is.Equal(info(i, p.Normalise(false).String()), info(i, test.imprecise))
+	}
+}
+
+func TestBigArithmetic(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		p, q     string
+		sum      string
+		diff     string
+		negative bool
+	}{
+		{"P1Y", "P2YT1S", "P3YT1S", "-P1YT1S", false},
+		{"P15000000000Y", "P15000000000Y", "P30000000000Y", "P0D", false},
+		{"PT0.000000000001S", "PT0.000000000002S", "PT0.000000000003S", "-PT0.000000000001S", false},
+		{"P1M", "P1D", "P1M1D", "P1M-1D", false},
+		{"-P1Y", "P1Y", "P0D", "-P2Y", true},
+	}
+
+	for i, test := range tests {
+		p, q := period.MustParseBig(test.p), period.MustParseBig(test.q)
+		is.Equal(info(i, p.Add(q).String()), info(i, test.sum))
+		is.Equal(info(i, p.Sub(q).String()), info(i, test.diff))
+		is.Equal(info(i, p.IsNegative()), info(i, test.negative))
+		is.True(p.Sub(p).IsZero())
+		is.Equal(info(i, p.Negate().Negate().String()), info(i, p.String()))
+		is.Equal(info(i, p.Abs().IsNegative()), info(i, false))
+	}
+}
+
+func TestBigScale(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input  string
+		factor string
+		want   string
+	}{
+		{"PT1.5S", "0.3", "PT0.45S"},
+		{"P1Y2M", "2", "P2Y4M"},
+		{"P1Y2M", "-1", "-P1Y2M"},
+		{"P1D", "0.5", "P0.5D"},
+		{"P15000000000Y", "1000000000000", "P15000000000000000000000Y"},
+		{"PT1S", "0.000000000001", "PT0.000000000001S"},
+		{"P1D", "0", "P0D"},
+	}
+
+	for i, test := range tests {
+		factor, _, err := apd.NewFromString(test.factor)
+		is.NoErr(err)
+		got, err := period.MustParseBig(test.input).Scale(factor)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, test.want))
+	}
+}
+
+func TestBigConversion(t *testing.T) {
+	is := is.New(t)
+
+	// Every Period converts to a BigPeriod and back exactly
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		p := period.NewPeriod(rnd.Int63()-rnd.Int63(), rnd.Int63n(24), 0, rnd.Int63n(1000), rnd.Int63(), 0)
+		if rnd.Intn(2) == 0 {
+			p = *p.Negate()
+		}
+		big := p.Big()
+		is.Equal(info(i, big.String()), info(i, p.String()))
+
+		back, err := big.Period()
+		is.NoErr(err)
+		is.True(back.Equal(p))
+	}
+
+	p := period.MustParse("-P1Y2M3DT4H5M6.000000007S", false)
+	is.Equal(p.Big().String(), "-P1Y2M3DT4H5M6.000000007S")
+
+	// Values that do not fit a Period
+	for i, input := range []string{
+		"P10000000000000000000Y",
+		"PT0.0000000001S",
+		"PT1.5H",
+	} {
+		_, err := period.MustParseBig(input).Period()
+		is.True(info(i, err) != info(i, nil)) // should not fit
+	}
+
+	// Normalising can make a big period fit
+	big := period.MustParseBig("PT1.5H")
+	p, err := big.Normalise(true).Period()
+	is.NoErr(err)
+	is.Equal(p.String(), "PT1H30M")
+}
@@ -0,0 +1,174 @@
+package period
+
+import (
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/apd"
+)
+
+// Periods are only partially ordered. Fields with a fixed ratio, such as hours
+// and minutes, can always be compared, but a month can be 28 to 31 days and a
+// day can be 23 to 25 hours across daylight saving changes. So whether P1M is
+// longer than P30D depends on when the period starts, and P1D and PT24H are
+// the same length on most days but not all.
+//
+// Equal is structural and never treats P1D as equal to PT24H. Compare is
+// calendar-exact from a reference time. CompareApprox gives a total order based
+// on a year of 365.2425 days, a month of 1/12 of that and days of 24 hours.
+
+// equalWeights are the weights of each field, from years to nanoseconds, within
+// its group of fields that have fixed ratios.
+var equalWeights = [8]int64{
+	12, 1, // years and months in months
+	7, 1, // weeks and days in days
+	3600 * int64(time.Second), 60 * int64(time.Second), int64(time.Second), 1, // hours to nanoseconds in nanoseconds
+}
+
+// approxWeights are the approximate lengths of each field in nanoseconds.
+var approxWeights = [8]int64{
+	daysPerYearE4 * (int64(nsOneDay) / oneE4),
+	daysPerYearE4 * (int64(nsOneDay) / oneE4) / 12,
+	7 * int64(nsOneDay),
+	int64(nsOneDay),
+	int64(nsOneHour),
+	int64(nsOneMinute),
+	int64(nsOneSecond),
+	1,
+}
+
+// Equal reports whether two periods are the same after canonicalisation, in
+// which whole years are made from months, whole weeks from days and whole
+// hours, minutes and seconds from smaller units. So P1Y equals P12M and PT1H
+// equals PT60M, but P1D does not equal PT24H and P1M does not equal P30D.
+func (p Period) Equal(that Period) bool {
+	a, b := p.signedFields(), that.signedFields()
+
+	groups := [][2]int{{0, 2}, {2, 4}, {4, 8}}
+	for _, g := range groups {
+		x := weightedTotal(a[g[0]:g[1]], equalWeights[g[0]:g[1]])
+		y := weightedTotal(b[g[0]:g[1]], equalWeights[g[0]:g[1]])
+		if x.Cmp(y) != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Compare orders two periods by adding each to the reference time with AddTo,
+// so the result is calendar-exact for that time. It returns -1 if p is shorter
+// than that, 0 if they are the same length and +1 if p is longer. The result
+// can differ between reference times; e.g. P1M is shorter than P30D from 1st
+// February but longer from 1st January.
+func (p Period) Compare(that Period, ref time.Time) (int, error) {
+	t1, _, err := p.AddTo(ref)
+	if err != nil {
+		return 0, err
+	}
+	t2, _, err := that.AddTo(ref)
+	if err != nil {
+		return 0, err
+	}
+
+	return compareTimes(t1, t2), nil
+}
+
+// CompareApprox orders two periods by their approximate lengths, taking a year
+// as 365.2425 days, a month as 1/12 of a year and a day as 24 hours. It returns
+// -1 if p is shorter than that, 0 if they are the same length and +1 if p is
+// longer. Unlike Compare this is a total order, but P1M and P30D compare as
+// they would in a month of 30.436875 days.
+func (p Period) CompareApprox(that Period) int {
+	return p.approxTotal().Cmp(that.approxTotal())
+}
+
+// SortAt sorts periods from shortest to longest from the reference time, as
+// with Compare. The sort is stable. If any period cannot be added to the
+// reference time, an error is returned and the slice is left unchanged.
+func SortAt(periods []Period, ref time.Time) error {
+	times := make([]time.Time, len(periods))
+	for i, p := range periods {
+		t, _, err := p.AddTo(ref)
+		if err != nil {
+			return err
+		}
+		times[i] = t
+	}
+
+	sort.Stable(&periodSorter{
+		periods: periods,
+		swap:    func(i, j int) { times[i], times[j] = times[j], times[i] },
+		less:    func(i, j int) bool { return compareTimes(times[i], times[j]) < 0 },
+	})
+
+	return nil
+}
+
+// SortApprox sorts periods from shortest to longest by their approximate
+// lengths, as with CompareApprox. The sort is stable.
+func SortApprox(periods []Period) {
+	totals := make([]*apd.Decimal, len(periods))
+	for i, p := range periods {
+		totals[i] = p.approxTotal()
+	}
+
+	sort.Stable(&periodSorter{
+		periods: periods,
+		swap:    func(i, j int) { totals[i], totals[j] = totals[j], totals[i] },
+		less:    func(i, j int) bool { return totals[i].Cmp(totals[j]) < 0 },
+	})
+}
+
+// periodSorter sorts periods along with precomputed keys.
+type periodSorter struct {
+	periods []Period
+	swap    func(i, j int)
+	less    func(i, j int) bool
+}
+
+func (s *periodSorter) Len() int {
+	return len(s.periods)
+}
+
+func (s *periodSorter) Less(i, j int) bool {
+	return s.less(i, j)
+}
+
+func (s *periodSorter) Swap(i, j int) {
+	s.periods[i], s.periods[j] = s.periods[j], s.periods[i]
+	s.swap(i, j)
+}
+
+func compareTimes(t1, t2 time.Time) int {
+	switch {
+	case t1.Before(t2):
+		return -1
+	case t1.After(t2):
+		return 1
+	}
+
+	return 0
+}
+
+// approxTotal gives the approximate length of the period in nanoseconds.
+func (p Period) approxTotal() *apd.Decimal {
+	fields := p.signedFields()
+	return weightedTotal(fields[:], approxWeights[:])
+}
+
+// weightedTotal gives the exact sum of each value multiplied by its weight.
+// Products of two int64 values and their sums cannot exceed the precision of
+// the context, so no error can arise.
+func weightedTotal(values, weights []int64) *apd.Decimal {
+	apdContext := apd.BaseContext.WithPrecision(100)
+
+	total := new(apd.Decimal)
+	term := new(apd.Decimal)
+	for i, v := range values {
+		apdContext.Mul(term, apd.New(v, 0), apd.New(weights[i], 0))
+		apdContext.Add(total, total, term)
+	}
+
+	return total
+}
@@ -0,0 +1,132 @@
+package period_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+func TestEqual(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		p, q string
+		want bool
+	}{
+		{"P1Y", "P12M", true},
+		{"P1Y1M", "P13M", true},
+		{"PT1H", "PT60M", true},
+		{"PT1M", "PT59.5S", false},
+		{"PT1S", "PT0.5S", false},
+		{"P7D", "P1W", true},
+		{"P0D", "PT0S", true},
+		{"-P1Y", "-P12M", true},
+		{"-P1Y", "P1Y", false},
+		{"P1D", "PT24H", false},
+		{"P1M", "P30D", false},
+		{"P1Y2M3DT4H5M6.5S", "P14M3DT245M6.5S", true},
+	}
+
+	for i, c := range cases {
+		p, q := period.MustParse(c.p, false), period.MustParse(c.q, false)
+		is.Equal(info(i, p.Equal(q)), info(i, c.want))
+		is.Equal(info(i, q.Equal(p)), info(i, c.want))
+	}
+
+	// Sums that were carried are equal to their inputs
+	p := period.MustParse("PT1H", false).Add(period.MustParse("-PT1M", false))
+	is.True(p.Equal(period.MustParse("PT59M", false)))
+}
+
+func TestCompare(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	jan := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)
+	dst := time.Date(2021, 3, 13, 12, 0, 0, 0, toronto)
+
+	cases := []struct {
+		p, q string
+		ref  time.Time
+		want int
+	}{
+		{"P1M", "P30D", jan, 1},
+		{"P1M", "P30D", feb, -1},
+		{"P1M", "P28D", feb, 0},
+		{"P1D", "PT24H", jan, 0},
+		{"P1D", "PT24H", dst, -1},
+		{"P1D", "PT23H", dst, 0},
+		{"-P1D", "P0D", jan, -1},
+		{"PT1.5S", "PT1S", jan, 1},
+	}
+
+	for i, c := range cases {
+		p, q := period.MustParse(c.p, false), period.MustParse(c.q, false)
+		got, err := p.Compare(q, c.ref)
+		is.NoErr(err)
+		is.Equal(info(i, got), info(i, c.want))
+
+		got, err = q.Compare(p, c.ref)
+		is.NoErr(err)
+		is.Equal(info(i, got), info(i, -c.want))
+	}
+}
+
+func TestCompareApprox(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		p, q string
+		want int
+	}{
+		{"P1M", "P30D", 1},
+		{"P1M", "P31D", -1},
+		{"P12M", "P365DT5H49M12S", 0},
+		{"P1D", "PT24H", 0},
+		{"P1W", "P6DT23H59M59.999S", 1},
+		{"-P1Y", "P0D", -1},
+		{"P1000000000Y", "P999999999Y12M", 0},
+	}
+
+	for i, c := range cases {
+		p, q := period.MustParse(c.p, false), period.MustParse(c.q, false)
+		is.Equal(info(i, p.CompareApprox(q)), info(i, c.want))
+		is.Equal(info(i, q.CompareApprox(p)), info(i, -c.want))
+	}
+}
+
+func TestSort(t *testing.T) {
+	is := is.New(t)
+
+	parse := func(values ...string) []period.Period {
+		var periods []period.Period
+		for _, v := range values {
+			periods = append(periods, period.MustParse(v, false))
+		}
+		return periods
+	}
+	strings := func(periods []period.Period) []string {
+		var values []string
+		for _, p := range periods {
+			values = append(values, p.String())
+		}
+		return values
+	}
+
+	periods := parse("P1M", "PT1H", "-P1D", "P30D", "P29D", "P0D")
+	is.NoErr(period.SortAt(periods, time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)))
+	is.Equal(strings(periods), []string{"-P1D", "P0D", "PT1H", "P1M", "P29D", "P30D"})
+
+	periods = parse("P1M", "PT1H", "-P1D", "P30D", "P29D", "P0D")
+	is.NoErr(period.SortAt(periods, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)))
+	is.Equal(strings(periods), []string{"-P1D", "P0D", "PT1H", "P29D", "P30D", "P1M"})
+
+	periods = parse("P1M", "PT1H", "-P1D", "P30D", "P31D", "P0D", "PT24H", "P1D")
+	period.SortApprox(periods)
+	is.Equal(strings(periods), []string{"-P1D", "P0D", "PT1H", "PT24H", "P1D", "P30D", "P1M", "P31D"})
+}
@@ -5,8 +5,8 @@
 package period
 
 import (
-	"fmt"
 	"strings"
+	"time"
 
 	"github.com/imarsman/datetime/xfmt"
 	"github.com/rickb777/plural"
@@ -36,18 +36,21 @@ var PeriodMinuteNames = plural.FromZero("", "%v minute", "%v minutes")
 var PeriodSecondNames = plural.FromZero("", "%v second", "%v seconds")
 
 // Format converts the period to human-readable form using the default localisation.
-// Multiples of 7 days are shown as weeks.
+// Multiples of 7 days are shown as weeks, along with any weeks in the period.
 func (p Period) Format() string {
 	return p.FormatWithPeriodNames(PeriodYearNames, PeriodMonthNames, PeriodWeekNames, PeriodDayNames, PeriodHourNames, PeriodMinuteNames, PeriodSecondNames)
 }
 
 // FormatWithoutWeeks converts the period to human-readable form using the default localisation.
-// Multiples of 7 days are not shown as weeks.
+// Multiples of 7 days are not shown as weeks, and any weeks in the period are
+// shown as days.
 func (p Period) FormatWithoutWeeks() string {
 	return p.FormatWithPeriodNames(PeriodYearNames, PeriodMonthNames, plural.Plurals{}, PeriodDayNames, PeriodHourNames, PeriodMinuteNames, PeriodSecondNames)
 }
 
 // FormatWithPeriodNames converts the period to human-readable form in a localisable way.
+// Plurals distinguish only zero, one and many; FormatLocale follows the CLDR
+// plural rules of a language instead.
 func (p Period) FormatWithPeriodNames(
 	yearNames, monthNames, weekNames, dayNames,
 	hourNames, minNames, secNames plural.Plurals) string {
@@ -58,19 +61,19 @@ func (p Period) FormatWithPeriodNames(
 	parts = appendNonBlank(parts, yearNames.FormatInt(int(p.years)))
 	parts = appendNonBlank(parts, monthNames.FormatInt(int(p.months)))
 
-	if p.days > 0 || (p.IsZero()) {
+	if p.weeks != 0 || p.days != 0 || p.IsZero() {
 		if len(weekNames) > 0 {
-			weeks := p.days / 70
-			mdays := p.days % 70
-			//fmt.Printf("%v %#v - %d %d\n", period, period, weeks, mdays)
-			if weeks > 0 {
+			// Whole weeks of days are shown as weeks along with any weeks kept
+			weeks := p.weeks + p.days/7
+			mdays := p.days % 7
+			if weeks != 0 {
 				parts = appendNonBlank(parts, weekNames.FormatInt(int(weeks)))
 			}
-			if mdays > 0 || weeks == 0 {
+			if mdays != 0 || weeks == 0 {
 				parts = appendNonBlank(parts, dayNames.FormatInt(int(mdays)))
 			}
 		} else {
-			parts = appendNonBlank(parts, dayNames.FormatInt(int(p.days)))
+			parts = appendNonBlank(parts, dayNames.FormatInt(int(p.weeks*7+p.days)))
 		}
 	}
 	parts = appendNonBlank(parts, hourNames.FormatInt(int(p.hours)))
@@ -87,33 +90,32 @@ func appendNonBlank(parts []string, s string) []string {
 	return append(parts, s)
 }
 
+// String formats the period using ISO-8601 designators, such as "P1Y2M3DT4H5M6.5S".
 func (p *Period) String() string {
+	// A stack buffer large enough for most periods avoids growing the slice
+	var buf [64]byte
+	return string(p.AppendString(buf[:0]))
+}
 
-	// fmt.Printf("years %d months %d days %d, hours %d minutes %d seconds %d nanoseconds %d\n", p.years, p.months, p.days, p.hours, p.minutes, p.seconds, p.nanoseconds)
-
-	// reduce := func(input int64) int64 {
-	// 	// hasRemainder := false
-	// 	var output int64
-	// 	output = input
-	// 	for {
-	// 		intPart := output / 10
-	// 		remainder := output % 10
-	// 		if remainder == 0 {
-	// 			output = intPart
-	// 			continue
-	// 		}
-	// 		break
-	// 	}
-	// 	return output
-	// }
+// XFmtString implements xfmt.Stringer so that a period can be written to an
+// xfmt.Buffer with Buffer.V.
+func (p Period) XFmtString(b []byte) []byte {
+	return p.AppendString(b)
+}
 
+// AppendString appends the ISO-8601 form of the period to b and returns the
+// extended buffer. No allocation occurs when b has enough capacity. An
+// xfmt.Buffer can be passed directly since it is a byte slice.
+func (p Period) AppendString(b []byte) []byte {
 	// All zero parts equals "P0D"
 	if p.IsZero() == true {
-		return "P0D"
+		return append(b, "P0D"...)
 	}
 
+	// Using the buffer type directly keeps the chained calls allocation free
+	xfmt := xfmt.Buffer(b)
+
 	// Begin with negative if period is negative
-	xfmt := new(xfmt.Buffer)
 	if p.negative {
 		xfmt.C('-')
 	}
@@ -131,13 +133,14 @@ func (p *Period) String() string {
 		xfmt.D64(p.months).C(minuteMonthChar)
 	}
 
+	// With weeks
+	if p.weeks != 0 {
+		xfmt.D64(p.weeks).C(weekChar)
+	}
+
 	// With days
 	if p.days != 0 {
-		if p.days%70 == 0 {
-			xfmt.D64(p.days / 7).C(dayChar)
-		} else {
-			xfmt.D64(p.days).C(dayChar)
-		}
+		xfmt.D64(p.days).C(dayChar)
 	}
 
 	// If time section(s)
@@ -154,21 +157,60 @@ func (p *Period) String() string {
 		xfmt.D64(p.minutes).C(minuteMonthChar)
 	}
 
-	// With seconds
-	if p.seconds != 0 {
-		if p.nanoseconds != 0 {
-			nanoStr := fmt.Sprintf("%03d", p.nanoseconds)
-			nanoStr = strings.TrimRight(nanoStr, "0")
-			xfmt.D64(p.seconds).C(dotChar).S(nanoStr).C(secondChar)
-		} else {
-			xfmt.D64(p.seconds).C(secondChar)
+	// With seconds and any subsecond values
+	if p.seconds != 0 || p.nanoseconds != 0 {
+		seconds, nanoseconds := secondsAndFraction(p.seconds, p.nanoseconds)
+		if seconds < 0 || nanoseconds < 0 {
+			xfmt.C(negativeChar)
+		}
+		xfmt.D64(absInt64(seconds))
+		if nanoseconds != 0 {
+			xfmt = appendFraction(xfmt, absInt64(nanoseconds))
 		}
-		// If no seconds but subsection values
-	} else if p.nanoseconds != 0 {
-		nanoStr := fmt.Sprintf("%03d", p.nanoseconds)
-		nanoStr = strings.TrimRight(nanoStr, "0")
-		xfmt.C('0').C(dotChar).S(nanoStr).C(secondChar)
+		xfmt.C(secondChar)
+	}
+
+	return xfmt.Bytes()
+}
+
+// secondsAndFraction carries whole seconds out of the nanoseconds and gives
+// both the same sign, so that they can be written as one decimal number.
+func secondsAndFraction(seconds, nanoseconds int64) (int64, int64) {
+	seconds += nanoseconds / int64(time.Second)
+	nanoseconds %= int64(time.Second)
+
+	if seconds > 0 && nanoseconds < 0 {
+		seconds--
+		nanoseconds += int64(time.Second)
+	} else if seconds < 0 && nanoseconds > 0 {
+		seconds++
+		nanoseconds -= int64(time.Second)
+	}
+
+	return seconds, nanoseconds
+}
+
+func absInt64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// appendFraction appends nanoseconds as a decimal fraction of a second with
+// trailing zeros removed, e.g. 50000000 becomes ".05".
+func appendFraction(b []byte, nanoseconds int64) []byte {
+	var digits [9]byte
+	for i := len(digits) - 1; i >= 0; i-- {
+		digits[i] = byte('0' + nanoseconds%10)
+		nanoseconds /= 10
+	}
+
+	end := len(digits)
+	for end > 0 && digits[end-1] == '0' {
+		end--
 	}
 
-	return string(xfmt.Bytes())
+	b = append(b, dotChar)
+	return append(b, digits[:end]...)
 }
@@ -0,0 +1,228 @@
+package period
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/apd"
+)
+
+// humanUnit is a unit accepted by ParseHuman. The whole number goes in the
+// field and any fraction is carried into smaller fields, using the size of the
+// unit in nanoseconds, or in months for years.
+type humanUnit struct {
+	field int   // index of the field as with signedFields, or -1 for sub-second units
+	size  int64 // nanoseconds in the unit, or zero for years and months
+}
+
+var humanUnits = map[string]humanUnit{}
+
+func init() {
+	add := func(unit humanUnit, names ...string) {
+		for _, name := range names {
+			humanUnits[name] = unit
+		}
+	}
+
+	add(humanUnit{field: 0}, "y", "yr", "yrs", "year", "years")
+	add(humanUnit{field: 1}, "mo", "mos", "mon", "month", "months")
+	add(humanUnit{field: 2, size: approxWeights[2]}, "w", "wk", "wks", "week", "weeks")
+	add(humanUnit{field: 3, size: approxWeights[3]}, "d", "day", "days")
+	add(humanUnit{field: 4, size: approxWeights[4]}, "h", "hr", "hrs", "hour", "hours")
+	add(humanUnit{field: 5, size: approxWeights[5]}, "m", "min", "mins", "minute", "minutes")
+	add(humanUnit{field: 6, size: approxWeights[6]}, "s", "sec", "secs", "second", "seconds")
+	add(humanUnit{field: -1, size: 1000000}, "ms", "msec", "msecs", "millisecond", "milliseconds")
+	add(humanUnit{field: -1, size: 1000}, "us", "µs", "μs", "usec", "usecs", "microsecond", "microseconds")
+	add(humanUnit{field: -1, size: 1}, "ns", "nsec", "nsecs", "nanosecond", "nanoseconds")
+}
+
+// ParseHuman parses a period written in one of the forms commonly used in
+// configuration files, such as
+//
+//	1h30m            Go duration syntax, as with time.ParseDuration
+//	2d4h, 1w, 1y6mo  with d, w, mo and y units added
+//	90 minutes       spelled-out English units, singular or plural
+//	2 days, 4 hours and 5 seconds
+//
+// Input is not case sensitive, so "M" is minutes; use "mo" for months. An
+// ISO-8601 period such as "P1DT2H" is parsed as with Parse.
+//
+// Each unit is kept in its own field, so "90 minutes" is PT90M and "1 week" is
+// P1W. A fraction is carried exactly into smaller fields, so "1.5h" is PT1H30M
+// and "1.5 years" is P1Y6M. An error is returned rather than approximating, so
+// fractions of a month or fractions that do not give whole nanoseconds are
+// rejected. A leading sign applies to the whole period.
+func ParseHuman(value string) (Period, error) {
+	fail := func(reason string) (Period, error) {
+		return Period{}, fmt.Errorf("period.ParseHuman: cannot parse %q: %s", value, reason)
+	}
+
+	input := strings.ToLower(strings.TrimSpace(value))
+	negative := false
+	if strings.HasPrefix(input, "-") || strings.HasPrefix(input, "+") {
+		negative = input[0] == '-'
+		input = strings.TrimSpace(input[1:])
+	}
+
+	if strings.HasPrefix(input, "p") {
+		// ISO-8601 designators are upper case
+		iso := strings.ToUpper(input)
+		if negative {
+			iso = "-" + iso
+		}
+		return Parse(iso)
+	}
+	if input == "" {
+		return fail("it is blank")
+	}
+	if input == "0" {
+		return Period{}, nil
+	}
+
+	ctx := apd.BaseContext.WithPrecision(100)
+	var totals [8]apd.Decimal
+	components := 0
+	// separator is the "," or "and" since the last unit, which must be
+	// followed by another unit; only ", and" may be used together
+	separator := ""
+
+	for len(input) > 0 {
+		r, _ := utf8.DecodeRuneInString(input)
+		switch {
+		case unicode.IsSpace(r):
+			input = input[utf8.RuneLen(r):]
+			continue
+		case r == ',':
+			if components == 0 || separator != "" {
+				return fail("it has an empty part before \",\"")
+			}
+			input = input[1:]
+			separator = ","
+			continue
+		case unicode.IsLetter(r):
+			word := leadingLetters(input)
+			if word != "and" {
+				return fail(fmt.Sprintf("%q has no number", word))
+			}
+			if components == 0 || separator == "and" {
+				return fail("it has an empty part before \"and\"")
+			}
+			input = input[len(word):]
+			separator = word
+			continue
+		}
+
+		end := strings.IndexFunc(input, func(r rune) bool { return !unicode.IsDigit(r) && r != '.' })
+		if end < 0 {
+			return fail("the last number has no unit")
+		}
+		number := input[:end]
+		input = strings.TrimLeftFunc(input[end:], unicode.IsSpace)
+
+		word := leadingLetters(input)
+		unit, ok := humanUnits[word]
+		if word == "" {
+			return fail(fmt.Sprintf("%s has no unit", number))
+		} else if !ok {
+			return fail(fmt.Sprintf("unknown unit %q", word))
+		}
+		input = input[len(word):]
+
+		amount, _, err := apd.NewFromString(number)
+		if err != nil || strings.Count(number, ".") > 1 || number == "." {
+			return fail(fmt.Sprintf("bad number %q", number))
+		}
+		if err := unit.addTo(&totals, amount, ctx); err != nil {
+			return fail(err.Error())
+		}
+		components++
+		separator = ""
+	}
+
+	if components == 0 {
+		return fail("it has no units")
+	} else if separator != "" {
+		return fail(fmt.Sprintf("it ends with %q", separator))
+	}
+
+	var fields [8]int64
+	for i := range totals {
+		v, err := totals[i].Int64()
+		if err != nil {
+			return fail("integer overflow")
+		}
+		fields[i] = v
+		if negative {
+			fields[i] = -v
+		}
+	}
+
+	return fromSignedFields(fields)
+}
+
+// addTo adds an amount of the unit to the totals of each field, carrying any
+// fraction exactly into smaller fields.
+func (u humanUnit) addTo(totals *[8]apd.Decimal, amount *apd.Decimal, ctx *apd.Context) error {
+	whole, fraction := new(apd.Decimal), new(apd.Decimal)
+	if u.field >= 0 {
+		ctx.QuoInteger(whole, amount, apd.New(1, 0))
+		ctx.Sub(fraction, amount, whole)
+		ctx.Add(&totals[u.field], &totals[u.field], whole)
+	} else {
+		fraction = amount
+	}
+	if fraction.IsZero() {
+		return nil
+	}
+
+	switch u.field {
+	case 0:
+		months := new(apd.Decimal)
+		ctx.Mul(months, fraction, apd.New(12, 0))
+		if !isWhole(months) {
+			return fmt.Errorf("%s years is not a whole number of months", amount)
+		}
+		ctx.Add(&totals[1], &totals[1], months)
+		return nil
+	case 1:
+		return fmt.Errorf("%s months cannot be given exactly in smaller units", amount)
+	}
+
+	nanoseconds := new(apd.Decimal)
+	ctx.Mul(nanoseconds, fraction, apd.New(u.size, 0))
+	if !isWhole(nanoseconds) {
+		return fmt.Errorf("%s is not a whole number of nanoseconds", amount)
+	}
+
+	// Share the nanoseconds among the smaller fields, from days to nanoseconds
+	for field := 3; field < 8; field++ {
+		if field <= u.field {
+			continue
+		}
+		size := apd.New(approxWeights[field], 0)
+		part := new(apd.Decimal)
+		ctx.QuoInteger(part, nanoseconds, size)
+		ctx.Rem(nanoseconds, nanoseconds, size)
+		ctx.Add(&totals[field], &totals[field], part)
+	}
+
+	return nil
+}
+
+// isWhole reports whether a decimal is an integer.
+func isWhole(d *apd.Decimal) bool {
+	integer := new(apd.Decimal)
+	apd.BaseContext.WithPrecision(100).RoundToIntegralValue(integer, d)
+	return integer.Cmp(d) == 0
+}
+
+// leadingLetters gives the letters at the start of a string.
+func leadingLetters(s string) string {
+	end := strings.IndexFunc(s, func(r rune) bool { return !unicode.IsLetter(r) })
+	if end < 0 {
+		return s
+	}
+	return s[:end]
+}
@@ -0,0 +1,75 @@
+package period_test
+
+import (
+	"testing"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+func TestParseHuman(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		// Go duration syntax, with added units
+		{"1h30m", "PT1H30M"},
+		{"1h 30m", "PT1H30M"},
+		{"-1.5h", "-PT1H30M"},
+		{"+300ms", "PT0.3S"},
+		{"1500ms", "PT1.5S"},
+		{"2µs", "PT0.000002S"},
+		{"1us1ns", "PT0.000001001S"},
+		{"2d4h", "P2DT4H"},
+		{"1w", "P1W"},
+		{"1y6mo", "P1Y6M"},
+		{"0", "P0D"},
+		{"1H30M", "PT1H30M"},
+		// spelled-out units
+		{"90 minutes", "PT90M"},
+		{"1 minute", "PT1M"},
+		{"2 days 4 hours", "P2DT4H"},
+		{"1 week", "P1W"},
+		{"1.5 weeks", "P1W3DT12H"},
+		{"1.5 years", "P1Y6M"},
+		{"2 hrs 5 secs", "PT2H5S"},
+		{"1 year, 2 months and 3 days", "P1Y2M3D"},
+		{"3 days and 12 hours", "P3DT12H"},
+		{"2 days, 4 hours, and 5 seconds", "P2DT4H5S"},
+		{"1 h, 2 m, 3.25 s", "PT1H2M3.25S"},
+		// repeated units are added, as with time.ParseDuration
+		{"1h1h", "PT2H"},
+		// ISO-8601
+		{"P1DT2H", "P1DT2H"},
+		{"-P1Y", "-P1Y"},
+		{" P1D", "P1D"},
+		{"- P1D", "-P1D"},
+		{"+ P1D", "P1D"},
+		{"p1dt2h", "P1DT2H"},
+		{"  -p1w ", "-P7D"},
+		{" 2 days", "P2D"},
+		{"- 2 days", "-P2D"},
+	}
+
+	for i, test := range tests {
+		p, err := period.ParseHuman(test.value)
+		is.NoErr(err)
+		is.Equal(info(i, p.String()), info(i, test.want))
+	}
+}
+
+func TestParseHumanErrors(t *testing.T) {
+	is := is.New(t)
+
+	for _, value := range []string{
+		"", "   ", "h", "1", "1 fortnight", "1.5 months", "0.1 years",
+		"1..5h", "0.1ns", "hours 1", "1h and", "and", "99999999999999999999y",
+		"1 day and and 2 hours", "1 day,, 2 hours", "1 day and, 2 hours", ", 1 day", "and 1 day", "1 day,",
+		"- p1x", "--P1D",
+	} {
+		_, err := period.ParseHuman(value)
+		is.True(err != nil) // value should be rejected
+	}
+}
@@ -0,0 +1,210 @@
+package period
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	cldr "golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// LocaleStyle selects the length of the unit names used by FormatLocale.
+type LocaleStyle int
+
+const (
+	// LongStyle uses full unit names, e.g. "3 hours".
+	LongStyle LocaleStyle = iota
+	// ShortStyle uses abbreviated unit names, e.g. "3 hr".
+	ShortStyle
+	// NarrowStyle uses the shortest unit names, e.g. "3h".
+	NarrowStyle
+)
+
+// UnitNames holds the names of one unit for each CLDR plural category of a
+// language, such as One, Few and Many. A name usually contains a "%v"
+// placeholder for the number, but need not, e.g. the Arabic for one hour.
+// Other must be set; it is used for any category without a name.
+type UnitNames map[cldr.Form]string
+
+// LocaleUnits holds the unit names of a language in one style. If Weeks is nil,
+// weeks are shown as days.
+type LocaleUnits struct {
+	Years, Months, Weeks, Days, Hours, Minutes, Seconds UnitNames
+
+	Separator string // used between units, e.g. ", "
+}
+
+// Locale holds the unit names of a language in each style, for use by
+// FormatLocale.
+type Locale struct {
+	Long, Short, Narrow LocaleUnits
+
+	Decimal string // the decimal separator for fractions of a second, e.g. ","
+}
+
+var locales = struct {
+	sync.RWMutex
+	byTag map[language.Tag]Locale
+}{byTag: builtinLocales()}
+
+// RegisterLocale adds or replaces the locale used for a language. The locale
+// is also used for more specific tags that have no locale of their own, so a
+// locale registered for "pt" is used for "pt-BR". An error is returned if any
+// unit has no name for the Other category.
+func RegisterLocale(tag language.Tag, locale Locale) error {
+	for _, units := range []LocaleUnits{locale.Long, locale.Short, locale.Narrow} {
+		for _, names := range units.all() {
+			if names != nil && names[cldr.Other] == "" {
+				return fmt.Errorf("period.RegisterLocale: %v has a unit without a name for the Other category", tag)
+			}
+		}
+		if units.Years == nil || units.Months == nil || units.Days == nil ||
+			units.Hours == nil || units.Minutes == nil || units.Seconds == nil {
+			return errors.New("period.RegisterLocale: only weeks can be left without names")
+		}
+	}
+
+	locales.Lock()
+	defer locales.Unlock()
+	locales.byTag[tag] = locale
+
+	return nil
+}
+
+// LookupLocale finds the locale for a language tag, trying the tag itself and
+// then each of its parents, e.g. "de-CH" and then "de". If no locale is found,
+// the English locale is returned along with false.
+func LookupLocale(tag language.Tag) (Locale, bool) {
+	locale, _, ok := lookupLocale(tag)
+	return locale, ok
+}
+
+// lookupLocale finds the locale for a language tag as with LookupLocale, also
+// giving the tag whose plural rules apply, which is English if no locale is
+// found.
+func lookupLocale(tag language.Tag) (Locale, language.Tag, bool) {
+	locales.RLock()
+	defer locales.RUnlock()
+
+	for t := tag; ; t = t.Parent() {
+		if locale, ok := locales.byTag[t]; ok {
+			return locale, tag, true
+		}
+		if t.IsRoot() {
+			break
+		}
+	}
+
+	return locales.byTag[language.English], language.English, false
+}
+
+// FormatLocale converts the period to human-readable form in the language of
+// the tag, with unit names in the given style, e.g. "1 Jahr, 2 Monate" in
+// German. Plural forms follow the CLDR rules for the language, so Polish has
+// "2 lata" but "5 lat". As with Format, multiples of 7 days are shown as
+// weeks and a negative period is shown as its absolute value.
+//
+// Languages without a registered locale are formatted in English.
+func (p Period) FormatLocale(tag language.Tag, style LocaleStyle) string {
+	locale, tag, _ := lookupLocale(tag)
+
+	var units LocaleUnits
+	switch style {
+	case ShortStyle:
+		units = locale.Short
+	case NarrowStyle:
+		units = locale.Narrow
+	default:
+		units = locale.Long
+	}
+
+	p = p.Abs()
+
+	var parts []string
+	add := func(names UnitNames, v int64) {
+		if v != 0 {
+			parts = append(parts, names.format(tag, strconv.FormatInt(v, 10), locale.Decimal))
+		}
+	}
+
+	add(units.Years, p.years)
+	add(units.Months, p.months)
+	weeks, days := int64(0), p.weeks*7+p.days
+	if units.Weeks != nil {
+		// Whole weeks of days are shown as weeks along with any weeks kept
+		weeks, days = p.weeks+p.days/7, p.days%7
+	}
+	add(units.Weeks, weeks)
+	add(units.Days, days)
+	add(units.Hours, p.hours)
+	add(units.Minutes, p.minutes)
+
+	if p.seconds != 0 || p.nanoseconds != 0 {
+		seconds, nanoseconds := secondsAndFraction(p.seconds, p.nanoseconds)
+		number := strconv.FormatInt(absInt64(seconds), 10)
+		if seconds < 0 || nanoseconds < 0 {
+			number = "-" + number
+		}
+		if nanoseconds != 0 {
+			number += string(appendFraction(nil, absInt64(nanoseconds)))
+		}
+		parts = append(parts, units.Seconds.format(tag, number, locale.Decimal))
+	}
+
+	if len(parts) == 0 {
+		parts = append(parts, units.Days.format(tag, "0", locale.Decimal))
+	}
+
+	return strings.Join(parts, units.Separator)
+}
+
+// format writes a number, given in plain decimal form such as "12.5", with the
+// name for its plural category. The decimal point is written as the given
+// separator unless that is blank.
+func (names UnitNames) format(tag language.Tag, number, decimal string) string {
+	name, ok := names[pluralForm(tag, number)]
+	if !ok {
+		name = names[cldr.Other]
+	}
+	if decimal != "" {
+		number = strings.Replace(number, ".", decimal, 1)
+	}
+
+	return strings.Replace(name, "%v", number, 1)
+}
+
+// pluralForm finds the CLDR plural category of a number in plain decimal form.
+func pluralForm(tag language.Tag, number string) cldr.Form {
+	number = strings.TrimPrefix(number, "-")
+
+	whole, fraction := number, ""
+	if dot := strings.IndexByte(number, '.'); dot >= 0 {
+		whole, fraction = number[:dot], number[dot+1:]
+	}
+
+	// Operands that are too large can be passed modulo 10,000,000
+	const modulo = 10000000
+	i := operand(whole, modulo)
+	v := len(fraction)
+	t := strings.TrimRight(fraction, "0")
+	f := operand(fraction, modulo)
+
+	return cldr.Cardinal.MatchPlural(tag, i, v, len(t), f, operand(t, modulo))
+}
+
+// operand converts decimal digits to an int, modulo the given value.
+func operand(digits string, modulo int) int {
+	n := 0
+	for _, r := range digits {
+		n = (n*10 + int(r-'0')) % modulo
+	}
+	return n
+}
+
+// all gives the names of every unit in order from years to seconds.
+func (u LocaleUnits) all() []UnitNames {
+	return []UnitNames{u.Years, u.Months, u.Weeks, u.Days, u.Hours, u.Minutes, u.Seconds}
+}
@@ -0,0 +1,511 @@
+package period
+
+import (
+	cldr "golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+// The built-in unit names follow the CLDR unit patterns for durations in each
+// style. Languages with more plural categories than one and other give a name
+// for each category the language uses.
+
+// oneOther gives unit names for languages that distinguish one from other.
+func oneOther(one, other string) UnitNames {
+	return UnitNames{cldr.One: one, cldr.Other: other}
+}
+
+// invariant gives unit names that are the same for every plural category.
+func invariant(name string) UnitNames {
+	return UnitNames{cldr.Other: name}
+}
+
+// oneFewMany gives unit names for languages such as Polish and Russian. Other
+// is used for fractions.
+func oneFewMany(one, few, many, other string) UnitNames {
+	return UnitNames{cldr.One: one, cldr.Few: few, cldr.Many: many, cldr.Other: other}
+}
+
+// arabic gives unit names for each of the six Arabic plural categories.
+func arabic(zero, one, two, few, many, other string) UnitNames {
+	return UnitNames{cldr.Zero: zero, cldr.One: one, cldr.Two: two, cldr.Few: few, cldr.Many: many, cldr.Other: other}
+}
+
+func builtinLocales() map[language.Tag]Locale {
+	return map[language.Tag]Locale{
+		language.English: {
+			Long: LocaleUnits{
+				Years:     oneOther("%v year", "%v years"),
+				Months:    oneOther("%v month", "%v months"),
+				Weeks:     oneOther("%v week", "%v weeks"),
+				Days:      oneOther("%v day", "%v days"),
+				Hours:     oneOther("%v hour", "%v hours"),
+				Minutes:   oneOther("%v minute", "%v minutes"),
+				Seconds:   oneOther("%v second", "%v seconds"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     oneOther("%v yr", "%v yrs"),
+				Months:    oneOther("%v mth", "%v mths"),
+				Weeks:     oneOther("%v wk", "%v wks"),
+				Days:      oneOther("%v day", "%v days"),
+				Hours:     invariant("%v hr"),
+				Minutes:   invariant("%v min"),
+				Seconds:   invariant("%v sec"),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%vy"),
+				Months:    invariant("%vm"),
+				Weeks:     invariant("%vw"),
+				Days:      invariant("%vd"),
+				Hours:     invariant("%vh"),
+				Minutes:   invariant("%vm"),
+				Seconds:   invariant("%vs"),
+				Separator: " ",
+			},
+			Decimal: ".",
+		},
+
+		language.French: {
+			Long: LocaleUnits{
+				Years:     oneOther("%v an", "%v ans"),
+				Months:    invariant("%v mois"),
+				Weeks:     oneOther("%v semaine", "%v semaines"),
+				Days:      oneOther("%v jour", "%v jours"),
+				Hours:     oneOther("%v heure", "%v heures"),
+				Minutes:   oneOther("%v minute", "%v minutes"),
+				Seconds:   oneOther("%v seconde", "%v secondes"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     oneOther("%v an", "%v ans"),
+				Months:    invariant("%v m."),
+				Weeks:     invariant("%v sem."),
+				Days:      invariant("%v j"),
+				Hours:     invariant("%v h"),
+				Minutes:   invariant("%v min"),
+				Seconds:   invariant("%v s"),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%va"),
+				Months:    invariant("%vm."),
+				Weeks:     invariant("%vsem."),
+				Days:      invariant("%vj"),
+				Hours:     invariant("%vh"),
+				Minutes:   invariant("%vmin"),
+				Seconds:   invariant("%vs"),
+				Separator: " ",
+			},
+			Decimal: ",",
+		},
+
+		language.German: {
+			Long: LocaleUnits{
+				Years:     oneOther("%v Jahr", "%v Jahre"),
+				Months:    oneOther("%v Monat", "%v Monate"),
+				Weeks:     oneOther("%v Woche", "%v Wochen"),
+				Days:      oneOther("%v Tag", "%v Tage"),
+				Hours:     oneOther("%v Stunde", "%v Stunden"),
+				Minutes:   oneOther("%v Minute", "%v Minuten"),
+				Seconds:   oneOther("%v Sekunde", "%v Sekunden"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     invariant("%v J."),
+				Months:    invariant("%v Mon."),
+				Weeks:     invariant("%v Wo."),
+				Days:      invariant("%v Tg."),
+				Hours:     invariant("%v Std."),
+				Minutes:   invariant("%v Min."),
+				Seconds:   invariant("%v Sek."),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%v J"),
+				Months:    invariant("%v M"),
+				Weeks:     invariant("%v W"),
+				Days:      invariant("%v T"),
+				Hours:     invariant("%v Std."),
+				Minutes:   invariant("%v Min."),
+				Seconds:   invariant("%v s"),
+				Separator: " ",
+			},
+			Decimal: ",",
+		},
+
+		language.Spanish: {
+			Long: LocaleUnits{
+				Years:     oneOther("%v año", "%v años"),
+				Months:    oneOther("%v mes", "%v meses"),
+				Weeks:     oneOther("%v semana", "%v semanas"),
+				Days:      oneOther("%v día", "%v días"),
+				Hours:     oneOther("%v hora", "%v horas"),
+				Minutes:   oneOther("%v minuto", "%v minutos"),
+				Seconds:   oneOther("%v segundo", "%v segundos"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     invariant("%v a"),
+				Months:    invariant("%v m."),
+				Weeks:     invariant("%v sem."),
+				Days:      invariant("%v d"),
+				Hours:     invariant("%v h"),
+				Minutes:   invariant("%v min"),
+				Seconds:   invariant("%v s"),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%va"),
+				Months:    invariant("%vm"),
+				Weeks:     invariant("%vsem"),
+				Days:      invariant("%vd"),
+				Hours:     invariant("%vh"),
+				Minutes:   invariant("%vmin"),
+				Seconds:   invariant("%vs"),
+				Separator: " ",
+			},
+			Decimal: ",",
+		},
+
+		language.Italian: {
+			Long: LocaleUnits{
+				Years:     oneOther("%v anno", "%v anni"),
+				Months:    oneOther("%v mese", "%v mesi"),
+				Weeks:     oneOther("%v settimana", "%v settimane"),
+				Days:      oneOther("%v giorno", "%v giorni"),
+				Hours:     oneOther("%v ora", "%v ore"),
+				Minutes:   oneOther("%v minuto", "%v minuti"),
+				Seconds:   oneOther("%v secondo", "%v secondi"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     oneOther("%v anno", "%v anni"),
+				Months:    oneOther("%v mese", "%v mesi"),
+				Weeks:     invariant("%v sett."),
+				Days:      invariant("%v g"),
+				Hours:     invariant("%v h"),
+				Minutes:   invariant("%v min"),
+				Seconds:   invariant("%v s"),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%va"),
+				Months:    invariant("%vm"),
+				Weeks:     invariant("%vsett."),
+				Days:      invariant("%vg"),
+				Hours:     invariant("%vh"),
+				Minutes:   invariant("%vmin"),
+				Seconds:   invariant("%vs"),
+				Separator: " ",
+			},
+			Decimal: ",",
+		},
+
+		language.Portuguese: {
+			Long: LocaleUnits{
+				Years:     oneOther("%v ano", "%v anos"),
+				Months:    oneOther("%v mês", "%v meses"),
+				Weeks:     oneOther("%v semana", "%v semanas"),
+				Days:      oneOther("%v dia", "%v dias"),
+				Hours:     oneOther("%v hora", "%v horas"),
+				Minutes:   oneOther("%v minuto", "%v minutos"),
+				Seconds:   oneOther("%v segundo", "%v segundos"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     oneOther("%v ano", "%v anos"),
+				Months:    oneOther("%v mês", "%v meses"),
+				Weeks:     invariant("%v sem."),
+				Days:      oneOther("%v dia", "%v dias"),
+				Hours:     invariant("%v h"),
+				Minutes:   invariant("%v min"),
+				Seconds:   invariant("%v s"),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%va"),
+				Months:    invariant("%vm"),
+				Weeks:     invariant("%vsem."),
+				Days:      invariant("%vd"),
+				Hours:     invariant("%vh"),
+				Minutes:   invariant("%vmin"),
+				Seconds:   invariant("%vs"),
+				Separator: " ",
+			},
+			Decimal: ",",
+		},
+
+		language.Dutch: {
+			Long: LocaleUnits{
+				Years:     invariant("%v jaar"),
+				Months:    oneOther("%v maand", "%v maanden"),
+				Weeks:     oneOther("%v week", "%v weken"),
+				Days:      oneOther("%v dag", "%v dagen"),
+				Hours:     invariant("%v uur"),
+				Minutes:   oneOther("%v minuut", "%v minuten"),
+				Seconds:   oneOther("%v seconde", "%v seconden"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     invariant("%v jr"),
+				Months:    invariant("%v mnd"),
+				Weeks:     invariant("%v wk"),
+				Days:      oneOther("%v dag", "%v dagen"),
+				Hours:     invariant("%v uur"),
+				Minutes:   invariant("%v min"),
+				Seconds:   invariant("%v sec"),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%vj"),
+				Months:    invariant("%vm"),
+				Weeks:     invariant("%vw"),
+				Days:      invariant("%vd"),
+				Hours:     invariant("%vu"),
+				Minutes:   invariant("%vm"),
+				Seconds:   invariant("%vs"),
+				Separator: " ",
+			},
+			Decimal: ",",
+		},
+
+		language.Swedish: {
+			Long: LocaleUnits{
+				Years:     invariant("%v år"),
+				Months:    oneOther("%v månad", "%v månader"),
+				Weeks:     oneOther("%v vecka", "%v veckor"),
+				Days:      invariant("%v dygn"),
+				Hours:     oneOther("%v timme", "%v timmar"),
+				Minutes:   oneOther("%v minut", "%v minuter"),
+				Seconds:   oneOther("%v sekund", "%v sekunder"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     invariant("%v år"),
+				Months:    invariant("%v mån"),
+				Weeks:     invariant("%v v"),
+				Days:      invariant("%v d"),
+				Hours:     invariant("%v tim"),
+				Minutes:   invariant("%v min"),
+				Seconds:   invariant("%v s"),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%vå"),
+				Months:    invariant("%vm"),
+				Weeks:     invariant("%vv"),
+				Days:      invariant("%vd"),
+				Hours:     invariant("%vh"),
+				Minutes:   invariant("%vm"),
+				Seconds:   invariant("%vs"),
+				Separator: " ",
+			},
+			Decimal: ",",
+		},
+
+		language.Polish: {
+			Long: LocaleUnits{
+				Years:     oneFewMany("%v rok", "%v lata", "%v lat", "%v roku"),
+				Months:    oneFewMany("%v miesiąc", "%v miesiące", "%v miesięcy", "%v miesiąca"),
+				Weeks:     oneFewMany("%v tydzień", "%v tygodnie", "%v tygodni", "%v tygodnia"),
+				Days:      oneFewMany("%v dzień", "%v dni", "%v dni", "%v dnia"),
+				Hours:     oneFewMany("%v godzina", "%v godziny", "%v godzin", "%v godziny"),
+				Minutes:   oneFewMany("%v minuta", "%v minuty", "%v minut", "%v minuty"),
+				Seconds:   oneFewMany("%v sekunda", "%v sekundy", "%v sekund", "%v sekundy"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     oneFewMany("%v rok", "%v lata", "%v lat", "%v roku"),
+				Months:    invariant("%v mies."),
+				Weeks:     oneFewMany("%v tydz.", "%v tyg.", "%v tyg.", "%v tyg."),
+				Days:      oneFewMany("%v dzień", "%v dni", "%v dni", "%v dnia"),
+				Hours:     invariant("%v godz."),
+				Minutes:   invariant("%v min"),
+				Seconds:   invariant("%v sek."),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%v r."),
+				Months:    invariant("%v m-c"),
+				Weeks:     invariant("%v tydz."),
+				Days:      invariant("%v d."),
+				Hours:     invariant("%v g."),
+				Minutes:   invariant("%v min"),
+				Seconds:   invariant("%v s"),
+				Separator: " ",
+			},
+			Decimal: ",",
+		},
+
+		language.Russian: {
+			Long: LocaleUnits{
+				Years:     oneFewMany("%v год", "%v года", "%v лет", "%v года"),
+				Months:    oneFewMany("%v месяц", "%v месяца", "%v месяцев", "%v месяца"),
+				Weeks:     oneFewMany("%v неделя", "%v недели", "%v недель", "%v недели"),
+				Days:      oneFewMany("%v день", "%v дня", "%v дней", "%v дня"),
+				Hours:     oneFewMany("%v час", "%v часа", "%v часов", "%v часа"),
+				Minutes:   oneFewMany("%v минута", "%v минуты", "%v минут", "%v минуты"),
+				Seconds:   oneFewMany("%v секунда", "%v секунды", "%v секунд", "%v секунды"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     oneFewMany("%v г.", "%v г.", "%v л.", "%v г."),
+				Months:    invariant("%v мес."),
+				Weeks:     invariant("%v нед."),
+				Days:      invariant("%v дн."),
+				Hours:     invariant("%v ч"),
+				Minutes:   invariant("%v мин"),
+				Seconds:   invariant("%v с"),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     oneFewMany("%v г.", "%v г.", "%v л.", "%v г."),
+				Months:    invariant("%v м."),
+				Weeks:     invariant("%v н."),
+				Days:      invariant("%v д"),
+				Hours:     invariant("%v ч"),
+				Minutes:   invariant("%v мин"),
+				Seconds:   invariant("%v с"),
+				Separator: " ",
+			},
+			Decimal: ",",
+		},
+
+		language.Arabic: {
+			Long: LocaleUnits{
+				Years:     arabic("%v سنة", "سنة واحدة", "سنتان", "%v سنوات", "%v سنة", "%v سنة"),
+				Months:    arabic("%v شهر", "شهر", "شهران", "%v أشهر", "%v شهرًا", "%v شهر"),
+				Weeks:     arabic("%v أسبوع", "أسبوع", "أسبوعان", "%v أسابيع", "%v أسبوعًا", "%v أسبوع"),
+				Days:      arabic("%v يوم", "يوم", "يومان", "%v أيام", "%v يومًا", "%v يوم"),
+				Hours:     arabic("%v ساعة", "ساعة", "ساعتان", "%v ساعات", "%v ساعة", "%v ساعة"),
+				Minutes:   arabic("%v دقيقة", "دقيقة", "دقيقتان", "%v دقائق", "%v دقيقة", "%v دقيقة"),
+				Seconds:   arabic("%v ثانية", "ثانية", "ثانيتان", "%v ثوانٍ", "%v ثانية", "%v ثانية"),
+				Separator: "، ",
+			},
+			Short: LocaleUnits{
+				Years:     arabic("%v سنة", "سنة واحدة", "سنتان", "%v سنوات", "%v سنة", "%v سنة"),
+				Months:    arabic("%v شهر", "شهر", "شهران", "%v أشهر", "%v شهرًا", "%v شهر"),
+				Weeks:     arabic("%v أسبوع", "أسبوع", "أسبوعان", "%v أسابيع", "%v أسبوعًا", "%v أسبوع"),
+				Days:      arabic("%v يوم", "يوم", "يومان", "%v أيام", "%v يومًا", "%v يوم"),
+				Hours:     invariant("%v س"),
+				Minutes:   invariant("%v د"),
+				Seconds:   invariant("%v ث"),
+				Separator: "، ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%v سنة"),
+				Months:    invariant("%v شهر"),
+				Weeks:     invariant("%v أ"),
+				Days:      invariant("%v ي"),
+				Hours:     invariant("%v س"),
+				Minutes:   invariant("%v د"),
+				Seconds:   invariant("%v ث"),
+				Separator: " ",
+			},
+			Decimal: ".",
+		},
+
+		language.Turkish: {
+			Long: LocaleUnits{
+				Years:     invariant("%v yıl"),
+				Months:    invariant("%v ay"),
+				Weeks:     invariant("%v hafta"),
+				Days:      invariant("%v gün"),
+				Hours:     invariant("%v saat"),
+				Minutes:   invariant("%v dakika"),
+				Seconds:   invariant("%v saniye"),
+				Separator: ", ",
+			},
+			Short: LocaleUnits{
+				Years:     invariant("%v yıl"),
+				Months:    invariant("%v ay"),
+				Weeks:     invariant("%v hf."),
+				Days:      invariant("%v gün"),
+				Hours:     invariant("%v sa."),
+				Minutes:   invariant("%v dk."),
+				Seconds:   invariant("%v sn."),
+				Separator: ", ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%vy"),
+				Months:    invariant("%va"),
+				Weeks:     invariant("%vh"),
+				Days:      invariant("%vg"),
+				Hours:     invariant("%vsa"),
+				Minutes:   invariant("%vdk"),
+				Seconds:   invariant("%vsn"),
+				Separator: " ",
+			},
+			Decimal: ",",
+		},
+
+		language.Japanese: {
+			Long: LocaleUnits{
+				Years:     invariant("%v 年"),
+				Months:    invariant("%v か月"),
+				Weeks:     invariant("%v 週間"),
+				Days:      invariant("%v 日"),
+				Hours:     invariant("%v 時間"),
+				Minutes:   invariant("%v 分"),
+				Seconds:   invariant("%v 秒"),
+				Separator: " ",
+			},
+			Short: LocaleUnits{
+				Years:     invariant("%v 年"),
+				Months:    invariant("%v か月"),
+				Weeks:     invariant("%v 週間"),
+				Days:      invariant("%v 日"),
+				Hours:     invariant("%v 時間"),
+				Minutes:   invariant("%v 分"),
+				Seconds:   invariant("%v 秒"),
+				Separator: " ",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%v年"),
+				Months:    invariant("%vか月"),
+				Weeks:     invariant("%v週間"),
+				Days:      invariant("%v日"),
+				Hours:     invariant("%v時間"),
+				Minutes:   invariant("%v分"),
+				Seconds:   invariant("%v秒"),
+				Separator: "",
+			},
+			Decimal: ".",
+		},
+
+		language.Chinese: {
+			Long: LocaleUnits{
+				Years:     invariant("%v年"),
+				Months:    invariant("%v个月"),
+				Weeks:     invariant("%v周"),
+				Days:      invariant("%v天"),
+				Hours:     invariant("%v小时"),
+				Minutes:   invariant("%v分钟"),
+				Seconds:   invariant("%v秒钟"),
+				Separator: "",
+			},
+			Short: LocaleUnits{
+				Years:     invariant("%v年"),
+				Months:    invariant("%v个月"),
+				Weeks:     invariant("%v周"),
+				Days:      invariant("%v天"),
+				Hours:     invariant("%v小时"),
+				Minutes:   invariant("%v分钟"),
+				Seconds:   invariant("%v秒"),
+				Separator: "",
+			},
+			Narrow: LocaleUnits{
+				Years:     invariant("%v年"),
+				Months:    invariant("%v个月"),
+				Weeks:     invariant("%v周"),
+				Days:      invariant("%v天"),
+				Hours:     invariant("%v小时"),
+				Minutes:   invariant("%v分钟"),
+				Seconds:   invariant("%v秒"),
+				Separator: "",
+			},
+			Decimal: ".",
+		},
+	}
+}
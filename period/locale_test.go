@@ -0,0 +1,126 @@
+package period_test
+
+import (
+	"testing"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+	cldr "golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+)
+
+func TestFormatLocale(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		tag    string
+		period string
+		long   string
+		short  string
+		narrow string
+	}{
+		{"en", "P1Y2M3DT1H1M1.5S", "1 year, 2 months, 3 days, 1 hour, 1 minute, 1.5 seconds", "1 yr, 2 mths, 3 days, 1 hr, 1 min, 1.5 sec", "1y 2m 3d 1h 1m 1.5s"},
+		{"en", "P0D", "0 days", "0 days", "0d"},
+		{"en", "-P11D", "1 week, 4 days", "1 wk, 4 days", "1w 4d"},
+		{"en-GB", "P1W", "1 week", "1 wk", "1w"},
+		{"fr", "P0D", "0 jour", "0 j", "0j"},
+		{"fr", "P2YT1.5S", "2 ans, 1,5 seconde", "2 ans, 1,5 s", "2a 1,5s"},
+		{"de", "P1Y2M", "1 Jahr, 2 Monate", "1 J., 2 Mon.", "1 J 2 M"},
+		{"de-CH", "PT1H0.25S", "1 Stunde, 0,25 Sekunden", "1 Std., 0,25 Sek.", "1 Std. 0,25 s"},
+		{"es", "P1Y3D", "1 año, 3 días", "1 a, 3 d", "1a 3d"},
+		{"it", "P2M1D", "2 mesi, 1 giorno", "2 mesi, 1 g", "2m 1g"},
+		{"pt-BR", "P2Y1D", "2 anos, 1 dia", "2 anos, 1 dia", "2a 1d"},
+		{"nl", "PT3H1M", "3 uur, 1 minuut", "3 uur, 1 min", "3u 1m"},
+		{"sv", "P2M1W", "2 månader, 1 vecka", "2 mån, 1 v", "2m 1v"},
+		{"pl", "P1Y", "1 rok", "1 rok", "1 r."},
+		{"pl", "P2Y", "2 lata", "2 lata", "2 r."},
+		{"pl", "P5Y", "5 lat", "5 lat", "5 r."},
+		{"pl", "P22Y", "22 lata", "22 lata", "22 r."},
+		{"pl", "PT1.5S", "1,5 sekundy", "1,5 sek.", "1,5 s"},
+		{"ru", "P1Y", "1 год", "1 г.", "1 г."},
+		{"ru", "P3D", "3 дня", "3 дн.", "3 д"},
+		{"ru", "P11D", "1 неделя, 4 дня", "1 нед., 4 дн.", "1 н. 4 д"},
+		{"ru", "P101Y", "101 год", "101 г.", "101 г."},
+		{"ar", "PT1H", "ساعة", "1 س", "1 س"},
+		{"ar", "PT2H", "ساعتان", "2 س", "2 س"},
+		{"ar", "P3D", "3 أيام", "3 أيام", "3 ي"},
+		{"ar", "P11Y", "11 سنة", "11 سنة", "11 سنة"},
+		{"tr", "P2DT3H", "2 gün, 3 saat", "2 gün, 3 sa.", "2g 3sa"},
+		{"ja", "P1Y2M", "1 年 2 か月", "1 年 2 か月", "1年2か月"},
+		{"zh", "P1Y2M", "1年2个月", "1年2个月", "1年2个月"},
+		// Languages without a locale are formatted in English
+		{"ko", "P1Y2D", "1 year, 2 days", "1 yr, 2 days", "1y 2d"},
+	}
+
+	for i, test := range tests {
+		p, err := period.Parse(test.period)
+		is.NoErr(err)
+
+		tag := language.MustParse(test.tag)
+		is.Equal(info(i, p.FormatLocale(tag, period.LongStyle)), info(i, test.long))
+		is.Equal(info(i, p.FormatLocale(tag, period.ShortStyle)), info(i, test.short))
+		is.Equal(info(i, p.FormatLocale(tag, period.NarrowStyle)), info(i, test.narrow))
+	}
+}
+
+func TestBuiltinLocales(t *testing.T) {
+	is := is.New(t)
+
+	tags := []language.Tag{
+		language.English, language.French, language.German, language.Spanish,
+		language.Italian, language.Portuguese, language.Dutch, language.Swedish,
+		language.Polish, language.Russian, language.Arabic, language.Turkish,
+		language.Japanese, language.Chinese,
+	}
+
+	p := period.MustParse("P1Y2M3W4DT5H6M7.5S", false)
+	for _, tag := range tags {
+		_, ok := period.LookupLocale(tag)
+		is.True(ok) // built in
+
+		for _, style := range []period.LocaleStyle{period.LongStyle, period.ShortStyle, period.NarrowStyle} {
+			is.True(p.FormatLocale(tag, style) != "")
+		}
+	}
+}
+
+func TestRegisterLocale(t *testing.T) {
+	is := is.New(t)
+
+	names := func(one, other string) period.UnitNames {
+		return period.UnitNames{cldr.One: one, cldr.Other: other}
+	}
+	units := period.LocaleUnits{
+		Years:     names("%v jaro", "%v jaroj"),
+		Months:    names("%v monato", "%v monatoj"),
+		Days:      names("%v tago", "%v tagoj"),
+		Hours:     names("%v horo", "%v horoj"),
+		Minutes:   names("%v minuto", "%v minutoj"),
+		Seconds:   names("%v sekundo", "%v sekundoj"),
+		Separator: " kaj ",
+	}
+
+	eo := language.MustParse("eo")
+	_, ok := period.LookupLocale(eo)
+	is.True(!ok) // not built in
+
+	err := period.RegisterLocale(eo, period.Locale{Long: units, Short: units, Narrow: units, Decimal: ","})
+	is.NoErr(err)
+
+	_, ok = period.LookupLocale(eo)
+	is.True(ok)
+
+	// Without week names weeks are shown as days
+	p := period.MustParse("P1Y2W1DT0.5S", false)
+	is.Equal(p.FormatLocale(eo, period.LongStyle), "1 jaro kaj 15 tagoj kaj 0,5 sekundoj")
+
+	// Every unit needs a name for the Other category
+	units.Days = period.UnitNames{cldr.One: "%v tago"}
+	err = period.RegisterLocale(eo, period.Locale{Long: units, Short: units, Narrow: units})
+	is.True(err != nil)
+
+	// Only weeks can be left out
+	units.Days = nil
+	err = period.RegisterLocale(eo, period.Locale{Long: units, Short: units, Narrow: units})
+	is.True(err != nil)
+}
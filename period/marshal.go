@@ -4,17 +4,130 @@ package period
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
+import (
+	"encoding/binary"
+	"errors"
+)
+
+// binaryVersion is the leading byte of the binary encoding. It can never be
+// the first byte of the legacy text form, which starts with 'P', '-' or '+'.
+const binaryVersion byte = 1
+
+// Presence bits for the binary encoding. A field is only written when it is
+// non-zero.
+const (
+	binaryYears uint64 = 1 << iota
+	binaryMonths
+	binaryWeeks
+	binaryDays
+	binaryHours
+	binaryMinutes
+	binarySeconds
+	binaryNanoseconds
+	binaryNegative
+)
+
 // MarshalBinary implements the encoding.BinaryMarshaler interface.
 // This also provides support for gob encoding.
+//
+// The encoding is a version byte followed by a varint presence bitmap, which
+// also carries the sign, and then a varint for each non-zero field in order
+// from years to nanoseconds. The zero period takes two bytes.
 func (period Period) MarshalBinary() ([]byte, error) {
-	// binary method would take more space in many cases, so we simply use text
-	return period.MarshalText()
+	fields := period.fields()
+
+	var flags uint64
+	for i, v := range fields {
+		if v != 0 {
+			flags |= 1 << uint(i)
+		}
+	}
+	if period.negative {
+		flags |= binaryNegative
+	}
+
+	b := make([]byte, 0, 2+len(fields)*binary.MaxVarintLen64)
+	b = append(b, binaryVersion)
+
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], flags)
+	b = append(b, buf[:n]...)
+
+	for _, v := range fields {
+		if v != 0 {
+			n = binary.PutVarint(buf[:], v)
+			b = append(b, buf[:n]...)
+		}
+	}
+
+	return b, nil
 }
 
 // UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
-// This also provides support for gob decoding.
+// This also provides support for gob decoding. The legacy text form written
+// by earlier versions is also accepted.
+//
+// Only the encoding written by MarshalBinary is accepted, so that equal
+// periods always decode from the same bytes: varints must be as short as
+// possible, fields marked present must not be zero and the zero period must
+// not be negative.
 func (period *Period) UnmarshalBinary(data []byte) error {
-	return period.UnmarshalText(data)
+	if len(data) == 0 {
+		return errors.New("period.UnmarshalBinary: no data")
+	}
+	if data[0] != binaryVersion {
+		return period.UnmarshalText(data)
+	}
+
+	data = data[1:]
+	flags, n := binary.Uvarint(data)
+	if n <= 0 || n != uvarintLen(flags) || flags >= binaryNegative<<1 || flags == binaryNegative {
+		return errors.New("period.UnmarshalBinary: invalid presence bitmap")
+	}
+	data = data[n:]
+
+	var fields [8]int64
+	for i := range fields {
+		if flags&(1<<uint(i)) == 0 {
+			continue
+		}
+		v, n := binary.Varint(data)
+		if n <= 0 || v == 0 || n != varintLen(v) {
+			return errors.New("period.UnmarshalBinary: invalid field value")
+		}
+		fields[i] = v
+		data = data[n:]
+	}
+
+	if len(data) > 0 {
+		return errors.New("period.UnmarshalBinary: unexpected trailing data")
+	}
+
+	*period = Period{
+		negative:    flags&binaryNegative != 0,
+		years:       fields[0],
+		months:      fields[1],
+		weeks:       fields[2],
+		days:        fields[3],
+		hours:       fields[4],
+		minutes:     fields[5],
+		seconds:     fields[6],
+		nanoseconds: fields[7],
+	}
+
+	return nil
+}
+
+// uvarintLen gives the length of the shortest encoding of v as a uvarint.
+func uvarintLen(v uint64) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutUvarint(buf[:], v)
+}
+
+// varintLen gives the length of the shortest encoding of v as a varint.
+func varintLen(v int64) int {
+	var buf [binary.MaxVarintLen64]byte
+	return binary.PutVarint(buf[:], v)
 }
 
 // MarshalText implements the encoding.TextMarshaler interface for Periods.
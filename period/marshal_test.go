@@ -6,8 +6,11 @@ package period_test
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/gob"
 	"encoding/json"
+	"math"
+	"math/rand"
 	"testing"
 
 	"github.com/imarsman/datetime/period"
@@ -50,6 +53,163 @@ func TestGobEncoding(t *testing.T) {
 	}
 }
 
+func TestBinaryEncoding(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []struct {
+		value string
+		size  int
+	}{
+		{"P0D", 2},
+		{"P1D", 3},
+		{"-P1D", 4},
+		{"P2Y3M4W5DT1H7M9.25S", 14},
+		{"-PT0.001S", 6},
+		{"P1000Y", 4},
+	}
+	for i, c := range cases {
+		p := period.MustParse(c.value, false)
+		bb, err := p.MarshalBinary()
+		g.Expect(err).NotTo(HaveOccurred(), info(i, c))
+		g.Expect(bb).To(HaveLen(c.size), info(i, c))
+
+		var p2 period.Period
+		err = p2.UnmarshalBinary(bb)
+		g.Expect(err).NotTo(HaveOccurred(), info(i, c))
+		g.Expect(p2).To(Equal(p), info(i, c))
+	}
+}
+
+func TestBinaryDecodingLegacyText(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := []string{
+		"P0D",
+		"-P2Y3M4W5DT1H7M9S",
+		"PT1.5S",
+	}
+	for i, c := range cases {
+		var p period.Period
+		err := p.UnmarshalBinary([]byte(c))
+		g.Expect(err).NotTo(HaveOccurred(), info(i, c))
+		g.Expect(p).To(Equal(period.MustParse(c, false)), info(i, c))
+	}
+}
+
+func TestBinaryDecodingErrors(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	cases := [][]byte{
+		nil,
+		{1},
+		{1, 0x80},
+		{1, 0x80, 0x04},
+		{1, 0x01},
+		{1, 0x01, 0x80},
+		{1, 0x00, 0x02},
+		// negative with no fields would be a negative zero
+		{1, 0x80, 0x02},
+		// overlong varints
+		{1, 0x80, 0x00},
+		{1, 0x81, 0x00, 0x02},
+		{1, 0x01, 0x82, 0x00},
+		// a field marked present must not be zero
+		{1, 0x01, 0x00},
+		{'x'},
+	}
+	for i, c := range cases {
+		var p period.Period
+		err := p.UnmarshalBinary(c)
+		g.Expect(err).To(HaveOccurred(), info(i, c))
+	}
+}
+
+// TestBinaryRoundTrip builds random encodings covering the whole range of each
+// field and checks they decode and re-encode to the same bytes.
+func TestBinaryRoundTrip(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rnd := rand.New(rand.NewSource(1))
+	extremes := []int64{1, -1, math.MaxInt64, math.MinInt64}
+
+	for i := 0; i < 10000; i++ {
+		flags := uint64(rnd.Intn(1 << 9))
+		if flags == 1<<8 {
+			// negative with no fields is rejected as a negative zero
+			flags = 0
+		}
+
+		data := []byte{1}
+		data = appendUvarint(data, flags)
+		for bit := uint(0); bit < 8; bit++ {
+			if flags&(1<<bit) == 0 {
+				continue
+			}
+			var v int64
+			switch rnd.Intn(3) {
+			case 0:
+				v = extremes[rnd.Intn(len(extremes))]
+			case 1:
+				v = rnd.Int63n(1000) + 1
+			default:
+				v = rnd.Int63() - rnd.Int63()
+			}
+			if v == 0 {
+				v = 1
+			}
+			data = appendVarint(data, v)
+		}
+
+		var p period.Period
+		err := p.UnmarshalBinary(data)
+		g.Expect(err).NotTo(HaveOccurred(), info(i, data))
+
+		bb, err := p.MarshalBinary()
+		g.Expect(err).NotTo(HaveOccurred(), info(i, data))
+		g.Expect(bb).To(Equal(data), info(i, p))
+
+		var p2 period.Period
+		err = p2.UnmarshalBinary(bb)
+		g.Expect(err).NotTo(HaveOccurred(), info(i, data))
+		g.Expect(p2).To(Equal(p), info(i, data))
+	}
+}
+
+// TestBinaryRoundTripParsed checks random parsed periods survive gob encoding.
+func TestBinaryRoundTripParsed(t *testing.T) {
+	g := NewGomegaWithT(t)
+
+	rnd := rand.New(rand.NewSource(2))
+
+	var b bytes.Buffer
+	encoder := gob.NewEncoder(&b)
+	decoder := gob.NewDecoder(&b)
+
+	for i := 0; i < 1000; i++ {
+		p := period.NewPeriod(
+			rnd.Int63n(10000), rnd.Int63n(100), rnd.Int63n(1000),
+			rnd.Int63n(1000), rnd.Int63n(1000), rnd.Int63n(100000))
+		if rnd.Intn(2) == 0 {
+			p.Negate()
+		}
+
+		var p2 period.Period
+		g.Expect(encoder.Encode(&p)).To(Succeed(), info(i, p))
+		g.Expect(decoder.Decode(&p2)).To(Succeed(), info(i, p))
+		g.Expect(p2).To(Equal(p), info(i, p))
+	}
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func appendVarint(b []byte, v int64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
 func TestPeriodJSONMarshalling(t *testing.T) {
 	g := NewGomegaWithT(t)
 
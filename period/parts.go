@@ -8,7 +8,10 @@ import (
 	"github.com/imarsman/datetime/timestamp"
 )
 
-// Period a struct to define a period
+// Period a struct to define a period. The negative flag is the sign of the
+// whole period and the fields are normally absolute values. Fields can also be
+// negative relative to the flag, for periods whose components have differing
+// signs, such as "P1Y-2M".
 type Period struct {
 	negative                                                         bool
 	years, months, weeks, days, hours, minutes, seconds, nanoseconds int64
@@ -30,20 +33,7 @@ const nsOneDay time.Duration = 24 * time.Hour                             // a d
 const nsOneMonthApprox time.Duration = oneMonthSeconds * nsOneSecond      // a month in nanoseconds - approximately
 const nsOoneYearApprox time.Duration = oneMonthSeconds * nsOneSecond * 12 // a year in nanoseconds - approximately
 
-// Use milliseconds for sub-second value in period to allow for 1 million times
-// longer periods without having to resort to arbitrary precision decimal
-// library, which is slower and uses more allocations.
-const msOneMillisecond = time.Millisecond / time.Duration(oneMillion) // a millisecond
-const msOneSecond = time.Second / time.Duration(oneMillion)           // a second of milliseconds
-const msOneMinute = time.Minute / time.Duration(oneMillion)           // a minute of milliseconds
-const msOneHour = time.Hour / time.Duration(oneMillion)               // an hour of milliseconds
-const msOneWeek = msOneDay * 7                                        // a week of milliseconds
-const msOneDay = nsOneDay / time.Duration(oneMillion)                 // a day of milliseconds
-const msOneMonthApprox = nsOneMonthApprox / time.Duration(oneMillion) // a month of milliseconds
-const msOneYearApprox = nsOoneYearApprox / time.Duration(oneMillion)  // a year of milliseconds
-
 // const daysPerMonthE6 = 30436875 // 30.436875 days per month
-const hundredMSDuration = 100 * time.Millisecond
 
 // https://en.wikipedia.org/wiki/Year
 // An average Gregorian year is 365.2425 days (52.1775 weeks, 8765.82 hours,
@@ -119,6 +109,15 @@ func (p Period) Months() int64 {
 	return p.months
 }
 
+// Weeks get weeks for period with proper sign. Weeks are only kept apart from
+// days when parsed with KeepWeeks or normalised with DaysToWeeks.
+func (p Period) Weeks() int64 {
+	if p.IsNegative() {
+		return -p.weeks
+	}
+	return p.weeks
+}
+
 // Days get days for period with proper sign
 func (p Period) Days() int64 {
 	if p.IsNegative() {
@@ -151,7 +150,21 @@ func (p Period) Seconds() int64 {
 	return p.seconds
 }
 
-// IsNegative is period negative
+// Nanoseconds get the fractional second nanoseconds for period with proper sign
+func (p Period) Nanoseconds() int64 {
+	if p.IsNegative() {
+		return -p.nanoseconds
+	}
+	return p.nanoseconds
+}
+
+// fields gives the stored absolute values from years to nanoseconds.
+func (p Period) fields() [8]int64 {
+	return [8]int64{p.years, p.months, p.weeks, p.days, p.hours, p.minutes, p.seconds, p.nanoseconds}
+}
+
+// IsNegative is period negative. For a period whose components have differing
+// signs this is false, and the sign of each component is given by its accessor.
 func (p *Period) IsNegative() bool {
 	return p.negative == true
 }
@@ -161,9 +174,9 @@ func (p *Period) IsPositive() bool {
 	return p.negative == false
 }
 
-// Negate changes the sign of the period.
+// Negate changes the sign of the period. A zero period stays positive.
 func (p *Period) Negate() *Period {
-	if p.IsNegative() {
+	if p.IsNegative() || p.IsZero() {
 		p.negative = false
 		return p
 	}
@@ -189,13 +202,11 @@ func (p Period) Duration() (time.Duration, bool, error) {
 	if err != nil {
 		return time.Duration(0), false, err
 	}
-	// fmt.Println("ymdDuration", ymdDuration)
 
 	hmsDuration, err := hmsDuration(p)
 	if err != nil {
 		return time.Duration(0), false, err
 	}
-	// fmt.Println("hmsDuration", hmsDuration)
 
 	_, ok := timestamp.DurationOverflows(ymdDuration, hmsDuration)
 	if ok == false {
@@ -0,0 +1,553 @@
+package period
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/apd"
+)
+
+// Predefined patterns for NewFormatter.
+const (
+	// CompactPattern gives periods such as "1y 2mo 3d 4h" or "1.5s".
+	CompactPattern = "{-}[{y}y][ {M}mo][ {w}w][ {d}d][ {h}h][ {m}m][ {s.*}s]"
+
+	// ClockPattern gives periods such as "26:03:04", with days and larger
+	// units carried into the hours.
+	ClockPattern = "{-}{h}:{mm}:{ss}"
+
+	// LongPattern gives periods such as "2 years, 3 months".
+	LongPattern = "{-}[{y| year| years}][, {M| month| months}][, {w| week| weeks}]" +
+		"[, {d| day| days}][, {h| hour| hours}][, {m| minute| minutes}][, {s.*| second| seconds}]"
+)
+
+// Indexes of the units that patterns can show, from years to seconds.
+const (
+	patternYears = iota
+	patternMonths
+	patternWeeks
+	patternDays
+	patternHours
+	patternMinutes
+	patternSeconds
+	patternUnitCount
+)
+
+// patternLetters are the placeholder letters of each unit.
+const patternLetters = "yMwdhms"
+
+// patternFixed are the lengths in nanoseconds of the units from weeks to
+// seconds.
+var patternFixed = [patternUnitCount]int64{
+	patternWeeks:   approxWeights[2],
+	patternDays:    approxWeights[3],
+	patternHours:   approxWeights[4],
+	patternMinutes: approxWeights[5],
+	patternSeconds: approxWeights[6],
+}
+
+// Formatter writes periods using a pattern that is compiled once by
+// NewFormatter. Periods are distributed among the units that the pattern
+// shows, so with ClockPattern P1DT2H3M4S is written as "26:03:04".
+//
+// A pattern is literal text with placeholders in braces:
+//
+//	{y} {M} {w} {d} {h} {m} {s}   years, months, weeks, days, hours, minutes and seconds
+//	{hh} {mm} {ss}                a repeated letter pads the number with zeros to that width
+//	{s.3}                         seconds with three decimal places
+//	{s.*}                         seconds with any fraction, without trailing zeros
+//	{d| day| days}                the number followed by the first text for one and the second otherwise
+//	{-}                           a minus sign if the period is negative
+//	{~}                           the Approximate text if the value shown is not exact
+//
+// Text in square brackets is an optional section, which is left out when all
+// the units in it are zero. Literal text at the start of an optional section
+// is only written if something has already been written, so it can separate
+// sections, as in "[{h}h][ {m}m]". If every optional section is left out, the
+// last one is written so that, for example, a zero period is "0s". A backslash writes the next character
+// literally, as in "\{" or "\[".
+//
+// Units not shown are carried into the next smaller unit shown, or into a
+// fraction of the smallest unit shown. Carrying between months and smaller
+// units uses an average month of 30.436875 days, and days are 24 hours long.
+// The smallest unit shown, or the last of MaxUnits, is truncated or rounded.
+type Formatter struct {
+	// MaxUnits, if positive, limits the output to that many of the largest
+	// non-zero units; smaller units are carried into the last one shown. This
+	// gives output such as "2 years, 3 months".
+	MaxUnits int
+	// Round rounds the last unit shown half away from zero, instead of
+	// truncating it.
+	Round bool
+	// ZeroUnits writes optional sections even when their units are zero.
+	ZeroUnits bool
+	// Weeks enables the {w} placeholder. When false, weeks are shown as days.
+	Weeks bool
+	// Approximate is written before the output when the value shown is not
+	// exact, because a remainder was dropped or rounded or because months were
+	// carried to or from smaller units. For example, "about ". It is written
+	// at the {~} placeholder, or at the start of the output if the pattern
+	// has none, so a negative period gives "about -1 day".
+	Approximate string
+
+	pattern     string
+	approximate bool // whether the pattern has a {~} placeholder
+	tokens      []patternToken
+	shown       [patternUnitCount]bool
+}
+
+// patternToken is a literal, a unit placeholder, a sign placeholder or an
+// approximate placeholder.
+type patternToken struct {
+	literal     string
+	unit        int  // the unit index, or -1 for a literal
+	sign        bool // a {-} placeholder
+	approximate bool // a {~} placeholder
+	width       int  // minimum number of digits
+	digits      int  // decimal places of seconds; -1 for any without trailing zeros
+	plural      bool // whether one and other are used
+	one, other  string
+	section     int // the optional section, or -1
+}
+
+// NewFormatter compiles a pattern into a Formatter, with weeks enabled. An
+// error is returned if the pattern is malformed or shows a unit more than once.
+func NewFormatter(pattern string) (*Formatter, error) {
+	f := &Formatter{pattern: pattern, Weeks: true}
+
+	fail := func(reason string) (*Formatter, error) {
+		return nil, fmt.Errorf("period.NewFormatter: %q %s", pattern, reason)
+	}
+
+	section, sections := -1, 0
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			f.tokens = append(f.tokens, patternToken{literal: literal.String(), unit: -1, section: section})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(pattern); i++ {
+		switch c := pattern[i]; c {
+		case '\\':
+			if i+1 == len(pattern) {
+				return fail("ends with a backslash")
+			}
+			i++
+			literal.WriteByte(pattern[i])
+
+		case '[':
+			if section >= 0 {
+				return fail("has nested optional sections")
+			}
+			flush()
+			section = sections
+			sections++
+
+		case ']':
+			if section < 0 {
+				return fail("has an unmatched ]")
+			}
+			flush()
+			section = -1
+
+		case '{':
+			end := strings.IndexByte(pattern[i:], '}')
+			if end < 0 {
+				return fail("has an unmatched {")
+			}
+			token, err := parsePlaceholder(pattern[i+1 : i+end])
+			if err != nil {
+				return fail(err.Error())
+			}
+			if token.unit >= 0 {
+				if f.shown[token.unit] {
+					return fail("shows a unit more than once")
+				}
+				f.shown[token.unit] = true
+			}
+			if token.approximate {
+				if f.approximate {
+					return fail("shows the approximate text more than once")
+				}
+				f.approximate = true
+			}
+			flush()
+			token.section = section
+			f.tokens = append(f.tokens, token)
+			i += end
+
+		case '}':
+			return fail("has an unmatched }")
+
+		default:
+			literal.WriteByte(c)
+		}
+	}
+	if section >= 0 {
+		return fail("has an unmatched [")
+	}
+	flush()
+
+	return f, nil
+}
+
+// MustNewFormatter is as with NewFormatter except that it panics if the
+// pattern is malformed. This is intended for setup code.
+func MustNewFormatter(pattern string) *Formatter {
+	f, err := NewFormatter(pattern)
+	if err != nil {
+		panic(err)
+	}
+	return f
+}
+
+// parsePlaceholder parses the inside of a placeholder, such as "ss.3" or
+// "d| day| days".
+func parsePlaceholder(s string) (patternToken, error) {
+	token := patternToken{unit: -1}
+	if s == "-" {
+		token.sign = true
+		return token, nil
+	}
+	if s == "~" {
+		token.approximate = true
+		return token, nil
+	}
+
+	if bar := strings.IndexByte(s, '|'); bar >= 0 {
+		forms := strings.Split(s[bar+1:], "|")
+		if len(forms) != 2 {
+			return token, fmt.Errorf("has a placeholder {%s} without both singular and plural text", s)
+		}
+		token.plural, token.one, token.other = true, forms[0], forms[1]
+		s = s[:bar]
+	}
+
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		digits := s[dot+1:]
+		switch {
+		case digits == "*":
+			token.digits = -1
+		case len(digits) == 1 && digits[0] >= '0' && digits[0] <= '9':
+			token.digits = int(digits[0] - '0')
+		default:
+			return token, fmt.Errorf("has a placeholder {%s} with bad decimal places", s)
+		}
+		s = s[:dot]
+	}
+
+	if s == "" || strings.Count(s, s[:1]) != len(s) || strings.IndexByte(patternLetters, s[0]) < 0 {
+		return token, fmt.Errorf("has an unknown placeholder {%s}", s)
+	}
+	token.unit = strings.IndexByte(patternLetters, s[0])
+	token.width = len(s)
+	if token.digits != 0 && token.unit != patternSeconds {
+		return token, fmt.Errorf("has decimal places on a unit other than seconds in {%s}", s)
+	}
+
+	return token, nil
+}
+
+// Pattern gives the pattern that the formatter was compiled from.
+func (f *Formatter) Pattern() string {
+	return f.pattern
+}
+
+// Format writes the period using the compiled pattern.
+func (f *Formatter) Format(p Period) string {
+	return string(f.AppendFormat(nil, p))
+}
+
+// AppendFormat appends the period written using the compiled pattern to b and
+// returns the extended buffer.
+func (f *Formatter) AppendFormat(b []byte, p Period) []byte {
+	negative := p.IsNegative()
+	if negative {
+		p = p.Abs()
+	}
+
+	values, exact := f.distribute(p)
+	if !exact && !f.approximate {
+		b = append(b, f.Approximate...)
+	}
+
+	// Work out which optional sections have units to show
+	visible := make(map[int]bool)
+	for _, t := range f.tokens {
+		if t.section >= 0 && f.enabled(t.unit) && (f.ZeroUnits || values[t.unit].Sign() != 0) {
+			visible[t.section] = true
+		}
+	}
+	if len(visible) == 0 {
+		// A zero period is shown using the last optional section with units
+		for i := len(f.tokens) - 1; i >= 0; i-- {
+			if t := f.tokens[i]; t.section >= 0 && f.enabled(t.unit) {
+				visible[t.section] = true
+				break
+			}
+		}
+	}
+
+	written := false
+	for i, t := range f.tokens {
+		if t.section >= 0 && !visible[t.section] && f.sectionHasUnits(t.section) {
+			continue
+		}
+		if t.unit >= 0 && !f.enabled(t.unit) {
+			continue
+		}
+		switch {
+		case t.sign:
+			if negative {
+				b = append(b, negativeChar)
+			}
+		case t.approximate:
+			if !exact {
+				b = append(b, f.Approximate...)
+			}
+		case t.unit < 0:
+			// A literal that starts a section separates it from earlier output
+			starts := t.section >= 0 && (i == 0 || f.tokens[i-1].section != t.section)
+			if starts && !written {
+				continue
+			}
+			b = append(b, t.literal...)
+			written = true
+		default:
+			b = appendPatternValue(b, values[t.unit], t)
+			written = true
+		}
+	}
+	return b
+}
+
+// enabled reports whether a unit placeholder is written, which excludes
+// literals and, unless Weeks is set, weeks.
+func (f *Formatter) enabled(unit int) bool {
+	return unit >= 0 && (unit != patternWeeks || f.Weeks)
+}
+
+// sectionHasUnits reports whether an optional section has any unit
+// placeholders.
+func (f *Formatter) sectionHasUnits(section int) bool {
+	for _, t := range f.tokens {
+		if t.section == section && t.unit >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// distribute shares the period among the units shown, giving the value of each
+// and whether the values are exact.
+func (f *Formatter) distribute(p Period) ([patternUnitCount]*apd.Decimal, bool) {
+	ctx := apd.BaseContext.WithPrecision(100)
+	shown := f.shown
+	if !f.Weeks {
+		shown[patternWeeks] = false
+	}
+
+	var values [patternUnitCount]*apd.Decimal
+	for i := range values {
+		values[i] = new(apd.Decimal)
+	}
+
+	fields := p.signedFields()
+	calendarShown := shown[patternYears] || shown[patternMonths]
+	fixedShown := false
+	for u := patternWeeks; u <= patternSeconds; u++ {
+		fixedShown = fixedShown || shown[u]
+	}
+	if !calendarShown && !fixedShown {
+		return values, true
+	}
+
+	// Exact totals of months and of nanoseconds
+	months := weightedTotal(fields[0:2], []int64{12, 1})
+	nanoseconds := weightedTotal(fields[2:8], approxWeights[2:8])
+	monthLength := apd.New(approxWeights[1], 0)
+	exact := true
+
+	toNanoseconds := func() {
+		if months.Sign() != 0 {
+			exact = false
+			term := new(apd.Decimal)
+			ctx.Mul(term, months, monthLength)
+			ctx.Add(nanoseconds, nanoseconds, term)
+			months = new(apd.Decimal)
+		}
+	}
+	toMonths := func() {
+		if nanoseconds.Sign() != 0 {
+			exact = false
+			term := new(apd.Decimal)
+			ctx.Quo(term, nanoseconds, monthLength)
+			ctx.Add(months, months, term)
+			nanoseconds = new(apd.Decimal)
+		}
+	}
+	if !calendarShown {
+		toNanoseconds()
+	}
+	if !fixedShown {
+		toMonths()
+	}
+
+	// The size of each unit in months or nanoseconds
+	size := func(u int) *apd.Decimal {
+		switch u {
+		case patternYears:
+			return apd.New(12, 0)
+		case patternMonths:
+			return apd.New(1, 0)
+		}
+		return apd.New(patternFixed[u], 0)
+	}
+	total := func(u int) *apd.Decimal {
+		if u <= patternMonths {
+			return months
+		}
+		return nanoseconds
+	}
+
+	// Find the last unit shown, allowing for MaxUnits
+	last, count := -1, 0
+	m, n := new(apd.Decimal).Set(months), new(apd.Decimal).Set(nanoseconds)
+	for u := range shown {
+		if !shown[u] {
+			continue
+		}
+		last = u
+		remaining := m
+		if u > patternMonths {
+			remaining = n
+		}
+		whole := new(apd.Decimal)
+		ctx.QuoInteger(whole, remaining, size(u))
+		if whole.Sign() != 0 {
+			count++
+			ctx.Rem(remaining, remaining, size(u))
+		}
+		if f.MaxUnits > 0 && count == f.MaxUnits {
+			break
+		}
+	}
+
+	// Anything smaller than the last unit is carried into it
+	if last <= patternMonths {
+		toMonths()
+	} else if !shown[patternMonths] {
+		// Months that do not make whole years go into the smaller units
+		if shown[patternYears] {
+			remainder := new(apd.Decimal)
+			ctx.Rem(remainder, months, apd.New(12, 0))
+			if remainder.Sign() != 0 {
+				exact = false
+				ctx.Sub(months, months, remainder)
+				term := new(apd.Decimal)
+				ctx.Mul(term, remainder, monthLength)
+				ctx.Add(nanoseconds, nanoseconds, term)
+			}
+		}
+	}
+
+	// Round the total to the resolution of the last unit
+	resolution := size(last)
+	if last == patternSeconds {
+		for _, t := range f.tokens {
+			if t.unit == patternSeconds {
+				resolution = secondsResolution(t.digits)
+			}
+		}
+	}
+	rounding := apd.RoundDown
+	if f.Round {
+		rounding = apd.RoundHalfUp
+	}
+	target := total(last)
+	steps := new(apd.Decimal)
+	ctx.Quo(steps, target, resolution)
+	rounded := new(apd.Decimal)
+	roundContext := *ctx
+	roundContext.Rounding = rounding
+	roundContext.RoundToIntegralValue(rounded, steps)
+	ctx.Mul(rounded, rounded, resolution)
+	if rounded.Cmp(target) != 0 {
+		exact = false
+	}
+	if last <= patternMonths {
+		months = rounded
+	} else {
+		nanoseconds = rounded
+	}
+
+	// Share the totals among the units down to the last one
+	for u := 0; u <= last; u++ {
+		if !shown[u] {
+			continue
+		}
+		remaining := total(u)
+		if u == last {
+			ctx.Quo(values[u], remaining, size(u))
+			ctx.Reduce(values[u], values[u])
+			break
+		}
+		ctx.QuoInteger(values[u], remaining, size(u))
+		ctx.Rem(remaining, remaining, size(u))
+	}
+
+	return values, exact
+}
+
+// secondsResolution gives the resolution in nanoseconds of seconds shown with
+// the given decimal places.
+func secondsResolution(digits int) *apd.Decimal {
+	if digits < 0 {
+		return apd.New(1, 0)
+	}
+	return apd.New(1, int32(9-digits))
+}
+
+// appendPatternValue appends the value of a unit as the placeholder requires.
+func appendPatternValue(b []byte, v *apd.Decimal, t patternToken) []byte {
+	text := ""
+	switch {
+	case t.digits > 0:
+		fixed := new(apd.Decimal)
+		apd.BaseContext.WithPrecision(100).Quantize(fixed, v, int32(-t.digits))
+		text = fixed.Text('f')
+	default:
+		text = v.Text('f')
+	}
+
+	if strings.HasPrefix(text, "-") {
+		b = append(b, negativeChar)
+		text = text[1:]
+	}
+	whole := strings.IndexByte(text, '.')
+	if whole < 0 {
+		whole = len(text)
+	}
+	for i := whole; i < t.width; i++ {
+		b = append(b, '0')
+	}
+	b = append(b, text...)
+
+	if t.plural {
+		if v.Cmp(apd.New(1, 0)) == 0 || v.Cmp(apd.New(-1, 0)) == 0 {
+			b = append(b, t.one...)
+		} else {
+			b = append(b, t.other...)
+		}
+	}
+
+	return b
+}
+
+// String gives the quoted pattern, which is useful when debugging.
+func (f *Formatter) String() string {
+	return strconv.Quote(f.pattern)
+}
@@ -0,0 +1,107 @@
+package period_test
+
+import (
+	"testing"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+func TestFormatterPatterns(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		pattern string
+		period  string
+		want    string
+	}{
+		{period.CompactPattern, "P1Y2M3DT4H", "1y 2mo 3d 4h"},
+		{period.CompactPattern, "P10D", "1w 3d"},
+		{period.CompactPattern, "PT1.5S", "1.5s"},
+		{period.CompactPattern, "-PT90M", "-1h 30m"},
+		{period.CompactPattern, "P0D", "0s"},
+		{period.ClockPattern, "P1DT2H3M4S", "26:03:04"},
+		{period.ClockPattern, "PT3M", "0:03:00"},
+		{period.ClockPattern, "-PT1H2.9S", "-1:00:02"},
+		{period.LongPattern, "P1Y2M", "1 year, 2 months"},
+		{period.LongPattern, "P2DT1S", "2 days, 1 second"},
+		{"{h}:{mm}:{ss.3}", "PT1M2.34567S", "0:01:02.345"},
+		{"{d}d {h}h", "PT36H", "1d 12h"},
+		{"{y}y {M}m", "P30M", "2y 6m"},
+		{"{M} months", "P1Y1M", "13 months"},
+		{"{d} days", "P1M", "30 days"},
+		{"{h}", "PT1H59M", "1"},
+		{"[{y}y][ {d}d]", "P1Y6M", "1y 182d"},
+		{"\\{{d}\\} \\[days\\]", "P2D", "{2} [days]"},
+	}
+
+	for i, test := range tests {
+		f := period.MustNewFormatter(test.pattern)
+		got := f.Format(period.MustParse(test.period, false))
+		is.Equal(info(i, got), info(i, test.want))
+	}
+}
+
+func TestFormatterOptions(t *testing.T) {
+	is := is.New(t)
+
+	p := period.MustParse("P2Y3M10DT5H", false)
+
+	f := period.MustNewFormatter(period.LongPattern)
+	f.MaxUnits = 2
+	f.Approximate = "about "
+	is.Equal(f.Format(p), "about 2 years, 3 months")
+
+	f.MaxUnits = 1
+	is.Equal(f.Format(p), "about 2 years")
+	f.Round = true
+	is.Equal(f.Format(p), "about 2 years")
+	is.Equal(f.Format(period.MustParse("P1Y7M", false)), "about 2 years")
+	is.Equal(f.Format(period.MustParse("P1Y", false)), "1 year")
+
+	// the approximate text is written at the start, before the sign
+	is.Equal(f.Format(period.MustParse("-P1Y7M", false)), "about -2 years")
+	is.Equal(f.Format(period.MustParse("-P1Y", false)), "-1 year")
+
+	// or where the pattern places it
+	placed := period.MustNewFormatter("{-}{~}" + period.LongPattern[len("{-}"):])
+	placed.MaxUnits = 1
+	placed.Round = true
+	placed.Approximate = "about "
+	is.Equal(placed.Format(period.MustParse("-P1Y7M", false)), "-about 2 years")
+	is.Equal(placed.Format(period.MustParse("-P1Y", false)), "-1 year")
+	suffix := period.MustNewFormatter("{-}{h}h{~}")
+	suffix.MaxUnits = 1
+	suffix.Approximate = " or so"
+	is.Equal(suffix.Format(period.MustParse("PT2H5M", false)), "2h or so")
+	is.Equal(suffix.Format(period.MustParse("PT2H", false)), "2h")
+
+	// rounding carries into the next unit
+	clock := period.MustNewFormatter("{h}:{mm}:{ss}")
+	clock.Round = true
+	is.Equal(clock.Format(period.MustParse("PT59M59.6S", false)), "1:00:00")
+	clock.Round = false
+	is.Equal(clock.Format(period.MustParse("PT59M59.6S", false)), "0:59:59")
+
+	compact := period.MustNewFormatter(period.CompactPattern)
+	compact.Weeks = false
+	is.Equal(compact.Format(period.MustParse("P10D", false)), "10d")
+	compact.ZeroUnits = true
+	is.Equal(compact.Format(period.MustParse("PT4H", false)), "0y 0mo 0d 4h 0m 0s")
+}
+
+func TestNewFormatterErrors(t *testing.T) {
+	is := is.New(t)
+
+	for _, pattern := range []string{
+		"{x}", "{hm}", "{h", "h}", "[{h}", "{h}]", "[[{h}]]", "{h}{h}",
+		"{h.3}", "{s.x}", "{d|day}", "{~}{h}{~}", "\\",
+	} {
+		_, err := period.NewFormatter(pattern)
+		is.True(err != nil) // pattern should be rejected
+	}
+
+	f, err := period.NewFormatter("{h}h")
+	is.NoErr(err)
+	is.Equal(f.Pattern(), "{h}h")
+}
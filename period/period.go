@@ -29,6 +29,7 @@ const minuteMonthChar = 'M' // M
 const secondChar = 'S'      // S
 const timeChar = 'T'        // T
 const negativeChar = '-'    // -
+const plusChar = '+'        // +
 const dotChar = '.'         // .
 const commaChar = ','       // ,
 
@@ -41,143 +42,49 @@ const commaChar = ','       // ,
 // it will contain zero in the years, months and days fields but the number of days may be up to 3275; this
 // reduces errors arising from the variable lengths of months. For larger time differences, greater than
 // 3276 hours, the days, months and years fields are used as well.
+//
+// Minutes, seconds and nanoseconds are always kept exactly, so no detail below
+// an hour is lost.
 func NewOf(duration time.Duration) (p Period, precise bool) {
-	var sign int64 = 1
-	d := duration
+	// The magnitude is unsigned so that the minimum duration can be negated
+	d := uint64(duration)
 	if duration < 0 {
-		sign = -1
-		d = -duration
+		p.negative = true
+		d = uint64(-duration)
 	}
 
-	sign10 := sign * 10
-	totalHours := int64(d / time.Hour)
+	totalHours := int64(d / uint64(time.Hour))
+	p.minutes = int64(d % uint64(time.Hour) / uint64(time.Minute))
+	p.seconds = int64(d % uint64(time.Minute) / uint64(time.Second))
+	p.nanoseconds = int64(d % uint64(time.Second))
 
-	// check for 16-bit overflow - occurs near the 4.5 month mark
 	if totalHours < 3277 {
 		// simple HMS case
-		minutes := d % time.Hour / time.Minute
-		seconds := d % time.Minute / hundredMSDuration
-
-		p = NewPeriod(0, 0, 0, sign10*totalHours, sign10*int64(minutes), sign*int64(seconds))
+		p.hours = totalHours
 		precise = true
 
 		return
 	}
 
 	totalDays := totalHours / 24 // ignoring daylight savings adjustments
+	p.hours = totalHours - totalDays*24
 
 	if totalDays < 3277 {
-		hours := totalHours - totalDays*24
-		minutes := d % time.Hour / time.Minute
-		seconds := d % time.Minute / hundredMSDuration
-
-		p = NewPeriod(0, 0, sign10*totalDays, sign10*hours, sign10*int64(minutes), sign*int64(seconds))
+		p.days = totalDays
 		precise = false
 
 		return
 	}
 
 	// TODO it is uncertain whether this is too imprecise and should be improved
-	years := (oneE4 * totalDays) / daysPerYearE4
-	months := ((oneE4 * totalDays) / daysPerMonthE4) - (12 * years)
-	hours := totalHours - totalDays*24
-	totalDays = ((totalDays * oneE4) - (daysPerMonthE4 * months) - (daysPerYearE4 * years)) / oneE4
-
-	p = NewPeriod(sign10*years, sign10*months, sign10*totalDays, sign10*hours, 0, 0)
+	p.years = (oneE4 * totalDays) / daysPerYearE4
+	p.months = ((oneE4 * totalDays) / daysPerMonthE4) - (12 * p.years)
+	p.days = ((totalDays * oneE4) - (daysPerMonthE4 * p.months) - (daysPerYearE4 * p.years)) / oneE4
 	precise = false
 
 	return
 }
 
-// Between converts the span between two times to a period. Based on the Gregorian conversion
-// algorithms of `time.Time`, the resultant period is precise.
-//
-// To improve precision, result is not always fully normalised; for time differences less than 3276 hours
-// (about 4.5 months), it will contain zero in the years, months and days fields but the number of hours
-// may be up to 3275; this reduces errors arising from the variable lengths of months. For larger time
-// differences (greater than 3276 hours) the days, months and years fields are used as well.
-//
-// Remember that the resultant period does not retain any knowledge of the calendar, so any subsequent
-// computations applied to the period can only be precise if they concern either the date (year, month,
-// day) part, or the clock (hour, minute, second) part, but not both.
-// TODO: This is inaccurate. Need to refactor to get proper number of years,
-// months, days between times.
-func Between(t1, t2 time.Time) (p Period) {
-	t1GTt2 := true
-	if t2.Before(t1) {
-		t1, t2, t1GTt2 = t2, t1, false
-	}
-
-	if t1.Location() != t2.Location() {
-		t2 = t2.In(t1.Location())
-	}
-
-	// year, month, day, hour, min, sec, subsecond := partsDiff(t1, t2)
-
-	// p = NewPeriod(year, month, day, hour, min, sec)
-	// p.subseconds = int(subsecond)
-	duration := t2.Sub(t1)
-	// It would be imprecise to try to get year, month, and day
-	var year int64 = 0
-	var month int64 = 0
-	var day int64 = 0
-	hour := duration.Hours()
-	min := duration.Minutes()
-	sec := duration.Seconds()
-	p = NewPeriod(year, month, day, int64(hour), int64(min), int64(sec))
-	// TODO: make this work with period subseconds
-	p.nanoseconds = int64(duration.Nanoseconds() / 1000000)
-	p.negative = t1GTt2 == false
-
-	// Do not estimate years, months, days
-	p = *p.Normalise(true)
-
-	return
-}
-
-// TODO: This is inaccurate
-// get year, month, day, hour, min, sec, hundredth of second diff between two times
-func partsDiff(t1, t2 time.Time) (year, month, day, hour, min, sec, subsecond int64) {
-	duration := t2.Sub(t1)
-
-	hh1, mm1, ss1 := t1.Clock()
-	hh2, mm2, ss2 := t2.Clock()
-
-	day = int64(duration / (24 * time.Hour))
-
-	hour = int64(hh2 - hh1)
-	min = int64(mm2 - mm1)
-	sec = int64(ss2 - ss1)
-	// TODO: Make sure this is really subseconds
-	// subsecond = int64(t2.Nanosecond()-t1.Nanosecond()) / 1000000
-	// number of nanoseconds as milliseconds
-	subsecond = int64(t2.Nanosecond()-t1.Nanosecond()) * 1000000
-
-	// Normalize negative values
-	if sec < 0 {
-		sec += 60
-		min--
-	}
-
-	if min < 0 {
-		min += 60
-		hour--
-	}
-
-	if hour < 0 {
-		hour += 24
-		// no need to reduce day - it's calculated differently.
-	}
-
-	y1, m1, d1 := t1.Date()
-	y2, m2, d2 := t2.Date()
-	year = int64(y2 - y1)
-	month = int64(m2 - m1)
-	day = int64(d2 - d1)
-
-	return
-}
-
 func (p Period) absNeg() (Period, bool) {
 	if p.IsNegative() {
 		p.negative = false
@@ -186,7 +93,8 @@ func (p Period) absNeg() (Period, bool) {
 	return p, false
 }
 
-// Abs converts a negative period to a positive one.
+// Abs converts a negative period to a positive one. A period whose components
+// have differing signs is returned unchanged.
 func (p Period) Abs() Period {
 	a, _ := p.absNeg()
 	return a
@@ -208,14 +116,9 @@ func (p Period) Abs() Period {
 // * PT1HnM becomes 60+n minutes for 0 < n <= 10
 // * PT1MnS becomes 60+n seconds for 0 < n <= 10
 //
-// At each step, if a fraction exists and would affect the calculation, the transformations
-// stop. Also, when not precise,
+// Also, when not precise, nanoseconds are discarded for periods of at least an hour.
 //
-// * for periods of at least ten years, month proper fractions are discarded
-// * for periods of at least a year, day proper fractions are discarded
-// * for periods of at least a month, hour proper fractions are discarded
-// * for periods of at least a day, minute proper fractions are discarded
-// * for periods of at least an hour, second proper fractions are discarded
+// Periods whose fields have differing signs are returned unchanged.
 //
 // The thresholds can be set using the varargs th parameter. By default, the thresholds a,
 // b, c, d are 6 months, 6 hours, 10 minutes, 10 seconds respectively as listed in the rules
@@ -230,87 +133,56 @@ func (p Period) Abs() Period {
 func (p *Period) Simplify(precise bool, th ...int) *Period {
 	switch len(th) {
 	case 0:
-		return p.doSimplify(precise, 60, 60, 100, 100)
+		return p.doSimplify(precise, 6, 6, 10, 10)
 	case 1:
-		return p.doSimplify(precise, int64(th[0]*10), int64(th[0]*10), int64(th[0]*10), int64(th[0]*10))
+		return p.doSimplify(precise, int64(th[0]), int64(th[0]), int64(th[0]), int64(th[0]))
 	case 2:
-		return p.doSimplify(precise, int64(th[0]*10), int64(th[0]*10), int64(th[1]*10), int64(th[1]*10))
+		return p.doSimplify(precise, int64(th[0]), int64(th[0]), int64(th[1]), int64(th[1]))
 	case 3:
-		return p.doSimplify(precise, int64(th[0]*10), int64(th[1]*10), int64(th[2]*10), int64(th[2]*10))
+		return p.doSimplify(precise, int64(th[0]), int64(th[1]), int64(th[2]), int64(th[2]))
 	default:
-		return p.doSimplify(precise, int64(th[0]*10), int64(th[1]*10), int64(th[2]*10), int64(th[3]*10))
+		return p.doSimplify(precise, int64(th[0]), int64(th[1]), int64(th[2]), int64(th[3]))
 	}
 }
 
 func (p *Period) doSimplify(precise bool, monthMax, hourMax, minuteMax, secondMax int64) *Period {
+	for _, v := range p.fields() {
+		if v < 0 {
+			return p
+		}
+	}
+
 	ap, neg := p.absNeg()
 
 	// single year is dropped if there are some months
-	if ap.years == 10 && 0 < ap.months && ap.months <= monthMax && ap.days == 0 {
-		ap.months += 120
+	if ap.years == 1 && 0 < ap.months && ap.months <= monthMax && ap.weeks == 0 && ap.days == 0 {
+		ap.months += 12
 		ap.years = 0
 	}
 
-	// if ap.months%10 != 0 && ap.months > 10 {
-	if ap.months > 10 {
-		// month fraction is dropped for periods of at least ten years (1:120)
-		months := ap.months / 10
-		if !precise && ap.years >= 100 && months == 0 {
-			ap.months = 0
-		}
-		return ap.condNegate(neg)
-	}
-
-	if ap.days%10 != 0 && ap.days > 10 {
-		// day fraction is dropped for periods of at least a year (1:365)
-		days := ap.days / 10
-		if !precise && (ap.years > 0 || ap.months >= 120) && days == 0 {
-			ap.days = 0
-		}
-		return ap.condNegate(neg)
-	}
-
-	if !precise && ap.days == 10 && ap.years == 0 && ap.months == 0 && 0 < ap.hours && ap.hours <= hourMax {
-		ap.hours += 240
+	// single day is dropped if there are some hours
+	if !precise && ap.days == 1 && ap.years == 0 && ap.months == 0 && ap.weeks == 0 &&
+		0 < ap.hours && ap.hours <= hourMax {
+		ap.hours += 24
 		ap.days = 0
 	}
 
-	if ap.hours%10 != 0 && ap.hours > 10 {
-		// hour fraction is dropped for periods of at least a month (1:720)
-		hours := ap.hours / 10
-		if !precise && (ap.years > 0 || ap.months > 0 || ap.days >= 300) && hours == 0 {
-			ap.hours = 0
-		}
-		return ap.condNegate(neg)
-	}
-
-	if ap.hours == 10 && 0 < ap.minutes && ap.minutes <= minuteMax {
-		ap.minutes += 600
+	// single hour is dropped if there are some minutes
+	if ap.hours == 1 && 0 < ap.minutes && ap.minutes <= minuteMax {
+		ap.minutes += 60
 		ap.hours = 0
 	}
 
-	if ap.minutes%10 != 0 && ap.minutes > 10 {
-		// minute fraction is dropped for periods of at least a day (1:1440)
-		minutes := ap.minutes / 10
-		if !precise &&
-			(ap.years > 0 || ap.months > 0 || ap.days > 0 || ap.hours >= 240) && minutes == 0 {
-			ap.minutes = 0
-		}
-		return ap.condNegate(neg)
-	}
-
-	if ap.minutes == 10 && ap.hours == 0 && 0 < ap.seconds && ap.seconds <= secondMax {
-		ap.seconds += 600
+	// single minute is dropped if there are some seconds
+	if ap.minutes == 1 && ap.hours == 0 && 0 < ap.seconds && ap.seconds <= secondMax {
+		ap.seconds += 60
 		ap.minutes = 0
 	}
 
-	if ap.seconds%10 != 0 {
-		// second fraction is dropped for periods of at least an hour (1:3600)
-		seconds := ap.seconds / 10
-		if !precise &&
-			(ap.years > 0 || ap.months > 0 || ap.days > 0 || ap.hours > 0 || ap.minutes >= 600) && seconds == 0 {
-			ap.seconds = 0
-		}
+	// nanoseconds are dropped for periods of at least an hour (1:3600)
+	if !precise && ap.nanoseconds != 0 &&
+		(ap.years > 0 || ap.months > 0 || ap.weeks > 0 || ap.days > 0 || ap.hours > 0 || ap.minutes >= 60) {
+		ap.nanoseconds = 0
 	}
 
 	return ap.condNegate(neg)
@@ -328,6 +200,7 @@ func (p *Period) condNegate(neg bool) *Period {
 func (p Period) IsZero() bool {
 	return p.Years() == 0 &&
 		p.Months() == 0 &&
+		p.weeks == 0 &&
 		p.Days() == 0 &&
 		p.Hours() == 0 &&
 		p.Minutes() == 0 &&
@@ -364,398 +237,278 @@ func (p *Period) validate() (err error) {
 	return
 }
 
-// Normalise normalise period
+// Normalise normalise period. Values are carried between fields that have a
+// fixed ratio, so that there are fewer than 12 months, 60 minutes and 60
+// seconds. Within each group of fields the values end up with the same sign, so
+// "PT1H-1M" becomes "PT59M", but "P1M-1D" is unchanged as months and days have
+// no fixed ratio.
+//
+// When precise is false, whole days are also made from hours, on the basis that
+// days are 24 hours long, which is not the case across daylight saving
+// changes. Weeks and days are left as they are; use NormaliseWithOptions to
+// convert between them.
 func (p *Period) Normalise(precise bool) *Period {
-	return p.normalise(precise)
+	var options NormaliseOption
+	if precise {
+		options = Precise
+	}
+	return p.normalise(options)
 }
 
-func (p *Period) normalise(precise bool) *Period {
-	return p.rippleUp(precise).AdjustToRight(precise)
-}
+// NormaliseOption changes the rules used by NormaliseWithOptions. Options can
+// be combined.
+type NormaliseOption uint
 
-// This can overflow with very large input values
-func hmsDuration(p Period) (time.Duration, error) {
-	hourDuration := time.Duration(p.hours) * nsOneHour
-	minuteDuration := time.Duration(p.minutes) * nsOneMinute
-	secondDuration := time.Duration(p.seconds) * nsOneSecond
-	subSecondDuration := time.Duration(p.nanoseconds)
+const (
+	// Precise keeps days and hours apart, as with Normalise(true).
+	Precise NormaliseOption = 1 << iota
 
-	_, ok := timestamp.DurationOverflows(hourDuration, minuteDuration, secondDuration, subSecondDuration)
-	if ok == false {
-		return time.Duration(0), errors.New("Hour, minute, and second duration exceeds maximum")
-	}
+	// WeeksToDays folds weeks into days, so "P1W3D" becomes "P10D".
+	WeeksToDays
 
-	hourminutesecondDuration := (hourDuration + minuteDuration + secondDuration + subSecondDuration)
+	// DaysToWeeks makes whole weeks from days, so "P10D" becomes "P1W3D". It
+	// takes precedence over WeeksToDays.
+	DaysToWeeks
+)
 
-	return hourminutesecondDuration, nil
+// NormaliseWithOptions normalises the period as with Normalise, using the
+// given options. Any conversion between weeks and days is done after days are
+// made from hours, so "PT168H" becomes "P1W" without Precise and with
+// DaysToWeeks.
+func (p *Period) NormaliseWithOptions(options NormaliseOption) *Period {
+	return p.normalise(options)
 }
 
-// This can overflow with very large input values
-func ymdApproxDuration(p Period) (time.Duration, error) {
-	yearDuration := time.Duration(p.years) * nsOoneYearApprox
-	monthDuration := time.Duration(p.months) * nsOneMonthApprox
-	dayDuration := time.Duration(p.days) * nsOneDay
+func (p *Period) normalise(options NormaliseOption) *Period {
+	fields := p.signedFields()
 
-	_, ok := timestamp.DurationOverflows(yearDuration, monthDuration, dayDuration)
-	if ok == false {
-		return time.Duration(0), errors.New("Year, month, and day duration exceeds maximum")
+	var zeros [8]int64
+	carry := func(from, to int, ratios ...int64) {
+		group, err := carryGroup(fields[from:to], zeros[from:to], ratios)
+		if err == nil {
+			copy(fields[from:to], group)
+		}
 	}
 
-	yearMonthDayDuration := yearDuration + monthDuration + dayDuration
-
-	return yearMonthDayDuration, nil
-}
-
-// This can overflow with very large input values though through the use of
-// millisecond level percision this is less likely. Overflow would be at about
-// 294 million years.
-func ymdApproxMS(p Period) (int64, error) {
-	yearsMS := p.years * int64(msOneYearApprox)
-	monthsMS := p.months * int64(msOneMonthApprox)
-	daysMS := p.days * int64(msOneDay)
-
-	_, ok := timestamp.Int64Overflows(yearsMS, monthsMS, daysMS)
-	if ok == false {
-		return 0, errors.New("years, months, and days milliseconds exceeds maximum")
+	carry(0, 2, 12) // years, months
+	if options&Precise != 0 {
+		carry(4, 8, 60, 60, int64(time.Second)) // hours to nanoseconds
+	} else {
+		carry(3, 8, 24, 60, 60, int64(time.Second)) // days to nanoseconds
 	}
 
-	yearsMonthsDaysMS := yearsMS + monthsMS + daysMS
-
-	return yearsMonthsDaysMS, nil
-}
-
-// This can overflow with very large input values though through the use of
-// millisecond level percision this is less likely. Overflow would be at about
-// 294 million years.
-func hmsMS(p Period) (int64, error) {
-	hoursMS := p.hours * int64(msOneHour)
-	minutesMS := p.minutes * int64(msOneMinute)
-	secondsMS := p.seconds * int64(msOneSecond)
-	subSecondsMS := int64(p.nanoseconds) / 1000000
-
-	_, ok := timestamp.Int64Overflows(hoursMS, minutesMS, secondsMS, subSecondsMS)
-	if ok == false {
-		return 0, errors.New("hours, minutes, seconds, and subseconds milliseconds exceeds maximum")
+	switch {
+	case options&DaysToWeeks != 0:
+		carry(2, 4, 7) // weeks, days
+	case options&WeeksToDays != 0:
+		days, err := weightedTotal(fields[2:4], equalWeights[2:4]).Int64()
+		if err == nil {
+			fields[2], fields[3] = 0, days
+		}
 	}
 
-	hoursMinuteSecondsMS := (hoursMS + minutesMS + secondsMS + subSecondsMS)
+	if normalised, err := fromSignedFields(fields); err == nil {
+		*p = normalised
+	}
 
-	return hoursMinuteSecondsMS, nil
+	return p
 }
 
-// rippleUp move values up through categories if boundaries are passed.
-// Note that the resolution for the results is to the millisecond as using
-// nanoseconds will overflow at about 292.471208677536 years.
-// Precise as true will result in no adjustment for years, months, and days.
-func (p *Period) rippleUp(precise bool) *Period {
-	hms, err := hmsMS(*p)
+// hmsDuration gives the duration of the hours, minutes, seconds and
+// nanoseconds, or an error if it overflows.
+func hmsDuration(p Period) (time.Duration, error) {
+	d, err := durationSum(
+		[]int64{p.hours, p.minutes, p.seconds, p.nanoseconds},
+		[]time.Duration{nsOneHour, nsOneMinute, nsOneSecond, 1})
 	if err != nil {
-		fmt.Println("error", err)
+		return time.Duration(0), errors.New("Hour, minute, and second duration exceeds maximum")
 	}
-	if err == nil {
-		hourNumber := int64(hms / int64(msOneHour))
-		remainder := int64(hms % int64(msOneHour))
 
-		minuteNumber := remainder / int64(msOneMinute)
-		remainder = int64(hms % int64(msOneMinute))
-
-		secondNumber := remainder / int64(msOneSecond)
-		remainder = remainder % int64(msOneSecond)
+	return d, nil
+}
 
-		p.hours = hourNumber
-		p.minutes = minuteNumber
-		p.seconds = secondNumber
-		// p.subseconds = int(remainder)
+// ymdApproxDuration gives the approximate duration of the years, months,
+// weeks and days, or an error if it overflows.
+func ymdApproxDuration(p Period) (time.Duration, error) {
+	d, err := durationSum(
+		[]int64{p.years, p.months, p.weeks, p.days},
+		[]time.Duration{nsOoneYearApprox, nsOneMonthApprox, 7 * nsOneDay, nsOneDay})
+	if err != nil {
+		return time.Duration(0), errors.New("Year, month, and day duration exceeds maximum")
 	}
 
-	if !precise {
-		yearMonthDayDuration, err := ymdApproxMS(*p)
-		if err == nil {
-			yearNumber := int64(yearMonthDayDuration / int64(msOneYearApprox))
-			remainder := int64(yearMonthDayDuration % int64(msOneYearApprox))
-
-			monthNumber := int64(remainder / int64(msOneMonthApprox))
-			remainder = int64(yearMonthDayDuration % int64(msOneMonthApprox))
-
-			dayNumber := int64(remainder / int64(msOneDay))
+	return d, nil
+}
 
-			p.years = yearNumber
-			p.months = monthNumber
-			p.days = dayNumber
+// durationSum gives the sum of each value times its unit, checking each
+// multiplication and addition for overflow.
+func durationSum(values []int64, units []time.Duration) (time.Duration, error) {
+	var total int64
+	for i, v := range values {
+		sum, err := sumOf(v, int64(units[i]), total)
+		if err != nil {
+			return time.Duration(0), err
 		}
+		total = sum
 	}
 
-	return p
+	return time.Duration(total), nil
 }
 
-// AdjustToRight attempts to remove fractions in higher-order fields by moving their value to the
-// next-lower-order field.
-//
-// The average number of days in a month is 30.436875 days, so getting a period
-// with months in nit will make the adjustment approximate.
-// Note that precise is a cascade of normalize from parse.
-func (p *Period) AdjustToRight(precise bool) *Period {
-	// remember that the fields are all fixed-point 1E1
-
-	y10 := p.years % 10
-	if p.years > 10 {
-		p.months += y10 * 12
-		p.years = (p.years / 10) * 10
-	}
+// decimalUnits are the approximate lengths in nanoseconds of the parts that a
+// fraction is carried into, from years down to nanoseconds. Years are 365 days
+// and months 1/12 of that, as for Duration.
+var decimalUnits = [7]int64{
+	int64(nsOoneYearApprox),
+	int64(nsOneMonthApprox),
+	int64(nsOneDay),
+	int64(nsOneHour),
+	int64(nsOneMinute),
+	int64(nsOneSecond),
+	1,
+}
 
-	m10 := p.months % 10
-	if !precise && p.months > 10 {
-		p.days += (m10 * daysPerMonthE6) / oneE6
-		p.months = (p.months / 10) * 10
+// AdditionsFromDecimalSection break down decimal section and get allocations to
+// various parts. The fractional part needs to be a decimal float. e.g. 0.05
+//
+// A float cannot hold every decimal fraction exactly, so the shortest decimal
+// form of the float is used. The parser passes the digits as written to
+// additionsFromDecimal instead.
+func AdditionsFromDecimalSection(part rune, whole int64, fractional float64) (
+	years, months, days, hours, minutes, seconds, nanoseconds int64, err error,
+) {
+	if fractional < 0 {
+		err = fmt.Errorf("Invalid negative fraction %v", fractional)
+		return years, months, days, hours, minutes, seconds, nanoseconds, err
 	}
 
-	d10 := p.days % 10
-	if p.days > 10 {
-		p.hours += d10 * 24
-		p.days = (p.days / 10) * 10
+	// A fractional value of one or more adds to the whole
+	digits := strconv.FormatFloat(fractional, 'f', -1, 64)
+	parts := strings.SplitN(digits, ".", 2)
+	carried, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return years, months, days, hours, minutes, seconds, nanoseconds, err
 	}
-
-	hh10 := p.hours % 10
-	if p.hours > 10 {
-		p.minutes += hh10 * 60
-		p.hours = (p.hours / 10) * 10
+	var ok bool
+	whole, ok = timestamp.Int64Overflows(whole, carried)
+	if ok == false {
+		err = fmt.Errorf("Integer overflow adding %v to %d", fractional, whole)
+		return years, months, days, hours, minutes, seconds, nanoseconds, err
 	}
 
-	mm10 := p.minutes % 10
-	if p.minutes > 10 {
-		p.seconds += mm10 * 60
-		p.minutes = (p.minutes / 10) * 10
+	fraction := ""
+	if len(parts) == 2 {
+		fraction = parts[1]
 	}
 
-	return p
+	return additionsFromDecimal(part, whole, fraction)
 }
 
-// AdditionsFromDecimalSection break down decimal section and get allocations to
-// various parts. The fractional part needs to be a decimal float. e.g. 0.05
-func AdditionsFromDecimalSection(part rune, whole int64, fractional float64) (
+// additionsFromDecimal breaks down a decimal section, given as the whole value
+// and the digits after the decimal point, into allocations to the various parts.
+// The fraction is carried exactly into smaller parts down to the nanosecond,
+// with anything smaller than a nanosecond truncated. Integer arithmetic is used
+// unless the fraction would overflow an int64 number of nanoseconds, in which
+// case the arbitrary precision decimal library is used.
+func additionsFromDecimal(part rune, whole int64, fraction string) (
 	years, months, days, hours, minutes, seconds, nanoseconds int64, err error,
 ) {
+	var fields [7]int64 // years, months, days, hours, minutes, seconds, nanoseconds
+	var index int       // the field that the whole value goes in
+	var unit int64      // the length of the part in nanoseconds
+
+	switch part {
+	case yearChar:
+		index = 0
+	case monthChar:
+		index = 1
+	case weekChar:
+		index = 2
+	case dayChar:
+		index = 2
+	case hourChar:
+		index = 3
+	case minuteChar:
+		index = 4
+	case secondChar:
+		index = 5
+	default:
+		err = fmt.Errorf("Invalid time part %v to float", part)
+		return years, months, days, hours, minutes, seconds, nanoseconds, err
+	}
+	unit = decimalUnits[index]
 
-	// fmt.Println(string(part), whole, fractional)
-	// fmt.Println("fraction", fractional)
-	// Check for whether a rune is a time part
-	var isTimePart = func(r rune) bool {
-		switch r {
-		case yearChar:
-			return true
-		case monthChar:
-			return true
-		case weekChar:
-			return true
-		case dayChar:
-			return true
-		case hourChar:
-			return true
-		case minuteChar:
-			return true
-		case secondChar:
-			return true
-		default:
-			return false
+	fields[index] = whole
+	next := index + 1 // the first part that the fraction is carried into
+
+	if part == weekChar {
+		// Whole weeks are given as days, and a fraction of a week can add days
+		if whole > math.MaxInt64/7 {
+			err = fmt.Errorf("Integer overflow with %d weeks", whole)
+			return years, months, days, hours, minutes, seconds, nanoseconds, err
 		}
+		fields[index] = whole * 7
+		unit *= 7
+		next = index
 	}
 
-	// It should be impossible for an invalid part to come in if the sending
-	// code is correct, but this provides an extra level of verification with no
-	// significant performance impact.
-	isTime := isTimePart(part)
-	// Exit with invalid characters
-	if isTime == false {
-		err := fmt.Errorf("Invalid time part %v to float", part)
+	// Trailing zeros do not change the fraction
+	fraction = strings.TrimRight(fraction, "0")
+
+	remainder, err := fractionOf(unit, fraction)
+	if err != nil {
 		return years, months, days, hours, minutes, seconds, nanoseconds, err
 	}
 
-	// Tested to work with up to 15 billion years. Max for each part is
-	// multiplied by 1000 because we are not using nanosecond but rather
-	// millisecond level accuracy.
-	// An error will be returned by the apd library if the precision is insufficient.
-
-	// Since the ISO-8601 standard allows for only one decimal section we can
-	// calculate if overflow would occur with 64 bit calculations and fail over
-	// to the slower arbitrary precision decimal library without needing to do
-	// sums across sections.
-	const maxYears = 290 * 1000                    // Maximum years before failing over to apd
-	const maxMonths = maxYears * 12 * 1000         // Maximum months before failing over to apd
-	const maxWeeks = maxYears * 12 * 7 * 52 * 1000 // Maximum weeks before failing over to apd
-	const maxDays = maxYears * 12 * 365 * 1000     // Maximum hours before failing over to apd
-
-	const maxHours = maxYears * 12 * 365 * 24 * 1000             // Maximum hours before failing over to apd
-	const maxMinutes = maxYears * 12 * 365 * 24 * 60 * 1000      // Maximum minutes before failing over to apd
-	const maxSeconds = maxYears * 12 * 365 * 24 * 60 * 60 * 1000 // Maximum seconds before failing over to apd
-
-	var msMultiplier int64 = 0 // relative value to multiply by based on period part
-
-	// Get an apd library fetch of too-large period part. Only run this if there
-	// would be overflow.
-	var getPartAPD = func(multiplier int64, value int64) (int64, error) {
-		apdContext := apd.BaseContext.WithPrecision(200) // context for large calculations if necessary
-
-		// Pre define values that are costly in terms of allocations and bytes used
-		var apdFullValue = new(apd.Decimal)
-		apdResult := new(apd.Decimal)
-		apdMultiplier := apd.New(multiplier, 0)
-		var err error
-		var condition apd.Condition
-
-		// Multiply incoming value by multiplier and assign to full value
-		condition, err = apdContext.Mul(apdFullValue, apd.New(value, 0), apdMultiplier)
-		if err != nil {
-			return 0, err
-		}
-		if condition.Inexact() == true {
-			return 0, fmt.Errorf("inexact calculation with %d", value)
-		}
+	// Carry the fraction into each smaller part in turn
+	for i := next; i < len(fields); i++ {
+		fields[i] += remainder / decimalUnits[i]
+		remainder %= decimalUnits[i]
+	}
 
-		// Divide full value by multiplier and assign to result
-		condition, err = apdContext.QuoInteger(apdResult, apdFullValue, apdMultiplier)
-		if err != nil {
-			return 0, err
-		}
+	return fields[0], fields[1], fields[2], fields[3], fields[4], fields[5], fields[6], nil
+}
 
-		if condition.Inexact() == true {
-			return 0, fmt.Errorf("inexact calculation with %d", value)
-		}
+// fractionOf gives the decimal fraction with the given digits of a unit in
+// nanoseconds, truncated to a whole nanosecond.
+func fractionOf(unit int64, digits string) (int64, error) {
+	if digits == "" {
+		return 0, nil
+	}
 
-		// Get int64 value for result
-		wholeValue, err := apdResult.Int64()
+	// With up to 18 digits the numerator and denominator both fit an int64
+	if len(digits) <= 18 {
+		numerator, err := strconv.ParseInt(digits, 10, 64)
 		if err != nil {
 			return 0, err
 		}
-
-		return wholeValue, nil
-	}
-
-	// Find the section that applies and set it
-	if part == yearChar {
-		msMultiplier = int64(msOneYearApprox)
-		// Only use arbitrary precision decimals if we would overflow an int64
-		if whole > maxYears {
-			years, err = getPartAPD(msMultiplier, whole)
-			if err != nil {
-				return 0, 0, 0, 0, 0, 0, 0, err
-			}
-		} else {
-			fullValue := whole * msMultiplier
-			years = fullValue / int64(msMultiplier)
-		}
-	} else if part == monthChar {
-		msMultiplier = int64(msOneMonthApprox)
-		// Only use arbitrary precision decimals if we would overflow an int64
-		if whole > maxMonths {
-			months, err = getPartAPD(msMultiplier, whole)
-			if err != nil {
-				return 0, 0, 0, 0, 0, 0, 0, err
-			}
-		} else {
-			fullValue := whole * msMultiplier
-			months = fullValue / int64(msMultiplier)
-		}
-	} else if part == weekChar {
-		// multiplier is one day but we need to set the whole portion to
-		// weeks * 7
-		msMultiplier = int64(msOneDay)
-		whole = whole * 7
-		if whole > maxWeeks {
-			days, err = getPartAPD(msMultiplier, whole)
-			if err != nil {
-				return 0, 0, 0, 0, 0, 0, 0, err
-			}
-		} else {
-			fullValue := whole * msMultiplier
-			days = fullValue / int64(msMultiplier)
-		}
-		// Fractional calculation will need to look at fraction in terms of weeks
-		msMultiplier = int64(msOneWeek)
-	} else if part == dayChar {
-		msMultiplier = int64(msOneDay)
-		// Only use arbitrary precision decimals if we would overflow an int64
-		if whole > maxDays {
-			days, err = getPartAPD(msMultiplier, whole)
-			if err != nil {
-				return 0, 0, 0, 0, 0, 0, 0, err
-			}
-		} else {
-			fullValue := whole * msMultiplier
-			days = fullValue / int64(msMultiplier)
-		}
-	} else if part == hourChar {
-		msMultiplier = int64(msOneHour)
-		// Only use arbitrary precision decimals if we would overflow an int64
-		if whole > maxHours {
-			hours, err = getPartAPD(msMultiplier, whole)
-			if err != nil {
-				return 0, 0, 0, 0, 0, 0, 0, err
-			}
-		} else {
-			fullValue := whole * msMultiplier
-			hours = fullValue / int64(msMultiplier)
-		}
-	} else if part == minuteChar {
-		msMultiplier = int64(msOneMinute)
-		// Only use arbitrary precision decimals if we would overflow an int64
-		if whole > maxMinutes {
-			minutes, err = getPartAPD(msMultiplier, whole)
-			if err != nil {
-				return 0, 0, 0, 0, 0, 0, 0, err
-			}
-		} else {
-			fullValue := whole * msMultiplier
-			minutes = fullValue / int64(msMultiplier)
+		denominator := int64(1)
+		for range digits {
+			denominator *= 10
 		}
-	} else if part == secondChar {
-		msMultiplier = int64(msOneSecond)
-		// Only use arbitrary precision decimals if we would overflow an int64
-		if whole > maxSeconds {
-			seconds, err = getPartAPD(msMultiplier, whole)
-			if err != nil {
-				return 0, 0, 0, 0, 0, 0, 0, err
-			}
-		} else {
-			fullValue := whole * msMultiplier
-			seconds = fullValue / int64(msMultiplier)
+
+		if numerator <= math.MaxInt64/unit {
+			return unit * numerator / denominator, nil
 		}
 	}
 
-	// We have already figured out the multiplier so don't need to do that again
-	var postMS = int64(fractional * float64(msMultiplier)) // nanosecond value for fractional part
-
-	// Go through successive stages of division and obtainin of a remainder to
-	// get the portions for a time part and to set up the next division and
-	// remainder calculations.
-
-	years += postMS / int64(msOneYearApprox)
-	remainder := postMS % int64(msOneYearApprox)
-
-	months += remainder / int64(msOneMonthApprox)
-	remainder = postMS % int64(msOneMonthApprox)
-
-	days += remainder / int64(msOneDay)
-	remainder = postMS % int64(msOneDay)
-
-	hours += remainder / int64(msOneHour)
-	remainder = postMS % int64(msOneHour)
-
-	minutes += remainder / int64(msOneMinute)
-	remainder = postMS % int64(msOneMinute)
+	// Only use arbitrary precision decimals if we would overflow an int64
+	apdContext := apd.BaseContext.WithPrecision(200)
 
-	// Subseconds are to the level of millisecond
-	seconds += remainder / int64(msOneSecond)
-	remainder = postMS % int64(msOneSecond)
+	value, _, err := apd.NewFromString("0." + digits)
+	if err != nil {
+		return 0, err
+	}
 
-	nanoseconds += remainder
-	if nanoseconds > 0 {
-		// Multiply by 100,000 since value is calculated as ms
-		// result is nanoseconds
-		nanoseconds *= 1000000000000
+	result := new(apd.Decimal)
+	if _, err = apdContext.Mul(result, value, apd.New(unit, 0)); err != nil {
+		return 0, err
+	}
+	if _, err = apdContext.QuoInteger(result, result, apd.New(1, 0)); err != nil {
+		return 0, err
 	}
 
-	// fmt.Printf("years %d months %d days %d, hours %d minutes %d seconds %d nanoseconds %d\n", years, months, days, hours, minutes, seconds, nanoseconds)
-	return years, months, days, hours, minutes, seconds, nanoseconds, nil
+	return result.Int64()
 }
 
 // MustParse is as per Parse except that it panics if the string cannot be parsed.
@@ -770,9 +523,33 @@ func MustParse(value string, normalise bool, precise ...bool) Period {
 	return d
 }
 
+// ParseOption changes the rules used when parsing periods. Options can be
+// combined.
+type ParseOption uint
+
+const (
+	// Strict rejects signs on individual components, e.g. "P1Y-2M", which are
+	// not part of ISO-8601. A single leading sign is still allowed.
+	Strict ParseOption = 1 << iota
+
+	// KeepWeeks keeps weeks as written, so "P3W" has three weeks and no days.
+	// By default weeks are folded into days, so "P3W" has 21 days. A fraction
+	// of a week is always given in days and smaller units.
+	KeepWeeks
+)
+
 // Parse parses strings that specify periods using ISO-8601 rules.
 //
-// In addition, a plus or minus sign can precede the period, e.g. "-P10D"
+// In addition, a plus or minus sign can precede the period, e.g. "-P10D", and
+// each component can have its own sign, e.g. "P1Y-2M3D" as produced by
+// java.time and XML Schema tools. A leading sign applies to the whole period,
+// so "-P1Y-2M" is minus one year plus two months. Use ParseWithOptions with
+// Strict to reject signed components, or with KeepWeeks to keep weeks rather
+// than folding them into days.
+//
+// The ISO-8601 alternative format is also accepted, both extended, as in
+// "P0001-02-10T02:30:00", and basic, as in "P00010210T023000". No component
+// can exceed its carry-over point, e.g. 12 months or 30 days, in this format.
 //
 // The zero value can be represented in several ways: all of the following
 // are equivalent: "P0Y", "P0M", "P0W", "P0D", "PT0H", PT0M", PT0S", and "P0".
@@ -794,6 +571,12 @@ func Parse(period string, precise ...bool) (p Period, err error) {
 // This method is deprecated and should not be used. It may be removed in a
 // future version.
 func ParseWithPrecise(period string, precise bool) (Period, error) {
+	return ParseWithOptions(period, 0)
+}
+
+// ParseWithOptions parses strings that specify periods as with Parse, using
+// the given options.
+func ParseWithOptions(period string, options ParseOption) (Period, error) {
 	if period == "" || period == "-" || period == "+" {
 		return Period{}, fmt.Errorf("period.ParseWithNormalise: cannot parse a blank string as a period")
 	}
@@ -803,7 +586,11 @@ func ParseWithPrecise(period string, precise bool) (Period, error) {
 		return *p, nil
 	}
 
-	p, err := parse(period, precise)
+	if isAlternative(period) {
+		return parseAlternative(period)
+	}
+
+	p, err := parse(period, options)
 	if err != nil {
 		return Period{}, err
 	}
@@ -812,7 +599,7 @@ func ParseWithPrecise(period string, precise bool) (Period, error) {
 }
 
 // GetParts get the parts of a period
-func parse(input string, precise bool) (Period, error) {
+func parse(input string, options ParseOption) (Period, error) {
 
 	var period = Period{}
 
@@ -872,7 +659,7 @@ func parse(input string, precise bool) (Period, error) {
 			return true
 		case periodChar:
 			return true
-		case negativeChar:
+		case negativeChar, plusChar:
 			return true
 		case timeChar:
 			return true
@@ -882,6 +669,10 @@ func parse(input string, precise bool) (Period, error) {
 	}
 
 	var inPeriod bool = false
+	var started bool             // whether the period indicator has been found
+	var negativePart bool        // whether the current part has a minus sign
+	var signedPart bool          // whether the current part has a sign
+	var negativeDecimalPart bool // whether the decimal part has a minus sign
 
 	var currentRank = yearRank
 
@@ -917,6 +708,8 @@ func parse(input string, precise bool) (Period, error) {
 			if r == periodChar {
 				if inPeriod == false {
 					inPeriod = true
+					started = true
+					signedPart = false
 
 					continue
 				}
@@ -926,9 +719,35 @@ func parse(input string, precise bool) (Period, error) {
 
 				return Period{}, errors.New(string(msg.Bytes()))
 			}
-			// Allow negative signs throughout. Ignore but set period negative state
-			if r == negativeChar {
-				period.negative = true
+			// A sign before the period indicator applies to the whole period
+			// and a sign within it applies to the part that follows.
+			if r == negativeChar || r == plusChar {
+				if !started {
+					if signedPart {
+						xfmt := new(xfmt.Buffer)
+						msg := xfmt.S("period.parse: only one leading sign allowed ").S(input)
+
+						return Period{}, errors.New(string(msg.Bytes()))
+					}
+					signedPart = true
+					period.negative = r == negativeChar
+
+					continue
+				}
+				if options&Strict != 0 {
+					xfmt := new(xfmt.Buffer)
+					msg := xfmt.S("period.parse: signed parts are not allowed in strict mode ").S(input)
+
+					return Period{}, errors.New(string(msg.Bytes()))
+				}
+				if signedPart || len(activePart) > 0 {
+					xfmt := new(xfmt.Buffer)
+					msg := xfmt.S("period.parse: sign must come once before a part ").S(input)
+
+					return Period{}, errors.New(string(msg.Bytes()))
+				}
+				signedPart = true
+				negativePart = r == negativeChar
 
 				continue
 			}
@@ -959,6 +778,9 @@ func parse(input string, precise bool) (Period, error) {
 				if err != nil {
 					return Period{}, err
 				}
+				if negativePart {
+					intVal = -intVal
+				}
 			}
 
 			if r == yearChar {
@@ -1124,7 +946,10 @@ func parse(input string, precise bool) (Period, error) {
 			if inDecimal == true {
 				inPeriod = false
 				inDecimal = false
+				negativeDecimalPart = negativePart
 			}
+			signedPart = false
+			negativePart = false
 
 			// Making a new slice will allocate more
 			activePart = activePart[:0]
@@ -1139,6 +964,13 @@ func parse(input string, precise bool) (Period, error) {
 		return Period{}, errors.New(string(msg.Bytes()))
 	}
 
+	if signedPart {
+		xfmt := new(xfmt.Buffer)
+		msg := xfmt.S("period.parse: sign must come once before a part ").S(input)
+
+		return Period{}, errors.New(string(msg.Bytes()))
+	}
+
 	if len(decimalPart) > 0 {
 		if int(currentSection) != int(decimalSection) {
 			return Period{}, fmt.Errorf("period.parse: %s decimal must be in last section %s not in %s",
@@ -1149,42 +981,40 @@ func parse(input string, precise bool) (Period, error) {
 			return Period{}, fmt.Errorf("period.parse: 2 parts needed but got %s" + fmt.Sprint(len(parts)))
 
 		}
-		whole, err := strconv.Atoi(parts[0])
+		whole, err := strconv.ParseInt(parts[0], 10, 64)
 		if err != nil {
 			return Period{}, err
 		}
 
-		// set fractionalal part to a floating point decimal
-		fractional, err := strconv.ParseFloat("."+parts[1], 64)
-		if err != nil {
-			return Period{}, err
-		}
-
-		// Get
-		years, months, days, hours, minutes, seconds, fraction, err := AdditionsFromDecimalSection(
-			decimalSection, int64(whole), fractional,
+		// Get the exact allocations from the digits as written
+		years, months, days, hours, minutes, seconds, fraction, err := additionsFromDecimal(
+			decimalSection, whole, parts[1],
 		)
 		if err != nil {
 			return Period{}, err
 		}
+		if negativeDecimalPart {
+			years, months, days, hours, minutes = -years, -months, -days, -hours, -minutes
+			seconds, fraction = -seconds, -fraction
+		}
 		period.years += years
 		period.months += months
 		period.days += days
 		period.hours += hours
 		period.minutes += minutes
 		period.seconds += seconds
-		// Subseconds are to the level of millisecond
-		fraction = fraction / 1000000
-		// nanoStr := fmt.Sprintf("%03d", nanoseconds)
-		// fmt.Println("nanoseconds", nanoseconds, "nanoStr", nanoStr)
 		period.nanoseconds += fraction
 	}
 
 	// fmt.Printf("1 years %d months %d days %d, hours %d minutes %d seconds %d nanoseconds %d\n", period.years, period.months, period.days, period.hours, period.minutes, period.seconds, period.nanoseconds)
 
-	period.days += period.weeks * 7
-	// Zero out weeks as we have put them in days
-	period.weeks = 0
+	if options&KeepWeeks == 0 {
+		period.days += period.weeks * 7
+		// Zero out weeks as we have put them in days
+		period.weeks = 0
+	}
 
-	return period, nil
+	// Signs on the parts are relative to the leading sign. Where every part is
+	// negative the sign is moved to the front, so "P-1Y-2M" becomes "-P1Y2M".
+	return fromSignedFields(period.signedFields())
 }
@@ -2,10 +2,12 @@ package period_test
 
 import (
 	"fmt"
+	"math"
 	"testing"
 	"time"
 
 	"github.com/imarsman/datetime/period"
+	"github.com/imarsman/datetime/xfmt"
 	"github.com/matryer/is"
 
 	// "golang.org/x/text/language"
@@ -129,7 +131,10 @@ func TestParsePeriod(t *testing.T) {
 	for _, test := range tests {
 		p, _ := period.Parse(test, false, true)
 		d, _, err := p.Duration()
-		is.NoErr(err)
+		if p.Years() < 290 {
+			// longer periods overflow a time.Duration
+			is.NoErr(err)
+		}
 		fmt.Printf("Input %-15s period %0-15s normalized %-20s duration %-15v\n",
 			test, p.String(), p.Normalise(false).String(), d)
 	}
@@ -138,42 +143,88 @@ func TestParsePeriod(t *testing.T) {
 func TestParsePeriodWithFractionalParts(t *testing.T) {
 	is := is.New(t)
 
-	tests := []string{
-		"P0D",
-		"PT0.5S",
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"P0D", "P0D"},
+		{"PT0.5S", "PT0.5S"},
 		// Missing leading zero
-		"PT.5S",
-		"PT0.5M",
+		{"PT.5S", "PT0.5S"},
+		{"PT0.5M", "PT30S"},
 		// Missing leading zero
-		"PT.5M",
-		"PT0.5H",
+		{"PT.5M", "PT30S"},
+		{"PT0.5H", "PT30M"},
 		// Missing leading zero
-		"PT.5H",
+		{"PT.5H", "PT30M"},
 		// Use comma instead
-		"PT1,5M",
-		"PT1.5M",
-		"P1.5M",
-		"P1.5Y",
-		"P260.5W",
-		"P1.5M",
-		"P1.5D",
-		"PT1.5H",
-		"PT1.5M",
-		"PT1.5S",
-		"PT1.05S",
-		"PT1.500S",
-		"PT1.567S",
-		"PT1H14M",
+		{"PT1,5M", "PT1M30S"},
+		{"PT1.5M", "PT1M30S"},
+		{"P1.5M", "P1M15DT5H"},
+		{"P1.5Y", "P1Y6M"},
+		{"P260.5W", "P1823DT12H"},
+		{"P1.5D", "P1DT12H"},
+		{"PT1.5H", "PT1H30M"},
+		{"PT1.5S", "PT1.5S"},
+		{"PT1.05S", "PT1.05S"},
+		{"PT1.500S", "PT1.5S"},
+		{"PT1.567S", "PT1.567S"},
+		{"PT1H14M", "PT1H14M"},
+		// Microseconds and nanoseconds
+		{"PT0.000001S", "PT0.000001S"},
+		{"PT0.000000001S", "PT0.000000001S"},
+		{"PT1.123456789S", "PT1.123456789S"},
+		{"-PT0.000001S", "-PT0.000001S"},
+		{"PT0.000001M", "PT0.00006S"},
+		{"PT0.000000001H", "PT0.0000036S"},
+		{"P0.000000001D", "PT0.0000864S"},
+		// Beyond nanoseconds the fraction is truncated
+		{"PT1.1234567891S", "PT1.123456789S"},
+		{"PT0.0000000001S", "P0D"},
+		// Fractions too long for int64 arithmetic
+		{"P0.123456789012345678901234Y", "P1M14DT15H28M53.298293333S"},
+		{"P1.000000000000000000001Y", "P1Y"},
+		// 15 billion years
+		{"P15000000000.5Y", "P15000000000Y6M"},
 	}
 
-	for _, test := range tests {
-		p, _ := period.Parse(test, true, true)
-		d, _, err := p.Duration()
+	for i, test := range tests {
+		p, err := period.Parse(test.input, true, true)
 		is.NoErr(err)
-		fmt.Printf("Input %-15s period %0-15s normalized %-20s duration %-15v\n",
-			test, p.String(), p.Normalise(false).String(), d)
+		is.Equal(info(i, p.String()), info(i, test.want))
+
+		_, _, err = p.Duration()
+		if p.Years() < 290 {
+			is.NoErr(err)
+		}
+	}
+}
+
+// TestDurationOverflow check that periods too long for a time.Duration give an
+// error rather than a wrapped value
+func TestDurationOverflow(t *testing.T) {
+	is := is.New(t)
+
+	for i, value := range []string{
+		"P1000Y", "-P1000Y", "P300Y", "P4000M", "P20000W", "P110000D", "PT3000000H",
+		"PT200000000M", "PT10000000000S", "P200YT1000000H",
+	} {
+		_, _, err := period.MustParse(value, false).Duration()
+		is.True(info(i, err) != info(i, nil)) // duration should overflow
 	}
 
+	for i, test := range []struct {
+		value string
+		want  time.Duration
+	}{
+		{"P290Y", 290 * 365 * 24 * time.Hour},
+		{"PT2562047H", 2562047 * time.Hour},
+		{"-PT2562047H", -2562047 * time.Hour},
+	} {
+		d, _, err := period.MustParse(test.value, false).Duration()
+		is.NoErr(err)
+		is.Equal(info(i, d), info(i, test.want))
+	}
 }
 
 // TestParsePeriodBad parse intentionally incorrect periods
@@ -195,6 +246,263 @@ func TestParsePeriodBad(t *testing.T) {
 
 }
 
+func TestParseMixedSigns(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"P1Y-2M3D", "P1Y-2M3D"},
+		{"P-1Y2M", "P-1Y2M"},
+		{"-P1Y-2M", "P-1Y2M"},
+		{"PT-30M", "-PT30M"},
+		{"P-1Y-2M", "-P1Y2M"},
+		{"-P-1D", "P1D"},
+		{"+P1D", "P1D"},
+		{"P+1D", "P1D"},
+		{"P1DT-1.5S", "P1DT-1.5S"},
+		{"PT1M-0.5S", "PT1M-0.5S"},
+		{"P-0.5D", "-PT12H"},
+		{"P1Y-1W", "P1Y-7D"},
+	}
+
+	for i, test := range tests {
+		p, err := period.Parse(test.input)
+		is.NoErr(err)
+		is.Equal(info(i, p.String()), info(i, test.want))
+
+		// The string form parses back to the same period
+		p2, err := period.Parse(p.String())
+		is.NoErr(err)
+		is.Equal(info(i, p2), info(i, p))
+	}
+
+	bad := []string{
+		"P1-2Y",
+		"P--1Y",
+		"+-P1D",
+		"P1Y2M-",
+	}
+
+	for i, test := range bad {
+		_, err := period.Parse(test)
+		is.True(err != nil) // bad sign
+		t.Log(i, err)
+	}
+}
+
+func TestParseStrict(t *testing.T) {
+	is := is.New(t)
+
+	_, err := period.ParseWithOptions("P1Y-2M", period.Strict)
+	is.True(err != nil) // signed parts are not allowed
+	_, err = period.ParseWithOptions("PT-30M", period.Strict)
+	is.True(err != nil) // signed parts are not allowed
+
+	p, err := period.ParseWithOptions("-P1Y2M", period.Strict)
+	is.NoErr(err)
+	is.Equal(p.String(), "-P1Y2M")
+}
+
+func TestNormalise(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input     string
+		precise   string
+		imprecise string
+	}{
+		{"PT90M", "PT1H30M", "PT1H30M"},
+		{"-PT90M", "-PT1H30M", "-PT1H30M"},
+		{"P14M", "P1Y2M", "P1Y2M"},
+		{"P15Y", "P15Y", "P15Y"},
+		{"PT1H-1M", "PT59M", "PT59M"},
+		{"P1Y-2M", "P10M", "P10M"},
+		{"P1M-1D", "P1M-1D", "P1M-1D"},
+		{"P1DT-1H", "P1DT-1H", "PT23H"},
+		{"PT25H", "PT25H", "P1DT1H"},
+		{"PT59M60.5S", "PT1H0.5S", "PT1H0.5S"},
+		{"P1Y-2MT-1H", "P10MT-1H", "P10MT-1H"},
+	}
+
+	for i, test := range tests {
+		p := period.MustParse(test.input, false)
+		is.Equal(info(i, p.Normalise(true).String()), info(i, test.precise))
+
+		p = period.MustParse(test.input, false)
+		is.Equal(info(i, p.Normalise(false).String()), info(i, test.imprecise))
+	}
+}
+
+func TestParseKeepWeeks(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input string
+		kept  string
+		weeks int64
+		days  int64
+	}{
+		{"P3W", "P3W", 3, 0},
+		{"P3W2D", "P3W2D", 3, 2},
+		{"-P3W", "-P3W", -3, 0},
+		{"P1Y-1W", "P1Y-1W", -1, 0},
+		{"P10W", "P10W", 10, 0},
+		{"P1.5W", "P10DT12H", 0, 10},
+		{"P0W", "P0D", 0, 0},
+	}
+
+	for i, test := range tests {
+		p, err := period.ParseWithOptions(test.input, period.KeepWeeks)
+		is.NoErr(err)
+		is.Equal(info(i, p.String()), info(i, test.kept))
+		is.Equal(info(i, p.Weeks()), info(i, test.weeks))
+		is.Equal(info(i, p.Days()), info(i, test.days))
+
+		// The string form parses back to the same period
+		p2, err := period.ParseWithOptions(p.String(), period.KeepWeeks)
+		is.NoErr(err)
+		is.Equal(info(i, p2), info(i, p))
+	}
+
+	// Kept weeks count towards the length of the period
+	p, err := period.ParseWithOptions("P1W", period.KeepWeeks)
+	is.NoErr(err)
+	is.True(!p.IsZero())
+	d, _, err := p.Duration()
+	is.NoErr(err)
+	is.Equal(d, 168*time.Hour)
+
+	// By default weeks are folded into days
+	p = period.MustParse("P3W", false)
+	is.Equal(p.Weeks(), int64(0))
+	is.Equal(p.Days(), int64(21))
+	is.Equal(p.String(), "P21D")
+}
+
+func TestNormaliseWeeks(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input   string
+		options period.NormaliseOption
+		want    string
+	}{
+		{"P10D", period.DaysToWeeks, "P1W3D"},
+		{"P70D", period.DaysToWeeks, "P10W"},
+		{"P1W3D", period.WeeksToDays, "P10D"},
+		{"P1W3D", period.DaysToWeeks | period.WeeksToDays, "P1W3D"},
+		{"P1W-3D", period.DaysToWeeks, "P4D"},
+		{"-P2W3D", period.WeeksToDays, "-P17D"},
+		{"PT168H", period.DaysToWeeks, "P1W"},
+		{"PT168H", period.DaysToWeeks | period.Precise, "PT168H"},
+		{"P1W3D", 0, "P1W3D"},
+		{"P1W3D", period.Precise, "P1W3D"},
+	}
+
+	for i, test := range tests {
+		p, err := period.ParseWithOptions(test.input, period.KeepWeeks)
+		is.NoErr(err)
+		is.Equal(info(i, p.NormaliseWithOptions(test.options).String()), info(i, test.want))
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input   string
+		precise bool
+		th      []int
+		want    string
+	}{
+		{"P1Y2M", true, nil, "P14M"},
+		{"P1Y7M", true, nil, "P1Y7M"},
+		{"P1Y7M", true, []int{7}, "P19M"},
+		{"P11Y2M", true, nil, "P11Y2M"},
+		{"P1Y2M3D", true, nil, "P1Y2M3D"},
+		{"P1DT2H", false, nil, "PT26H"},
+		{"P1DT2H", true, nil, "P1DT2H"},
+		{"P2DT2H", false, nil, "P2DT2H"},
+		{"PT1H5M", true, nil, "PT65M"},
+		{"PT1H11M", true, nil, "PT1H11M"},
+		{"PT1M5S", true, nil, "PT65S"},
+		{"PT1M5.5S", true, nil, "PT65.5S"},
+		{"PT2H3.5S", false, nil, "PT2H3S"},
+		{"PT2H3.5S", true, nil, "PT2H3.5S"},
+		{"-P1Y2M", true, nil, "-P14M"},
+		{"-PT1H5M", true, nil, "-PT65M"},
+		{"P1Y-2M", true, nil, "P1Y-2M"},
+	}
+
+	for i, test := range tests {
+		p, err := period.Parse(test.input)
+		is.NoErr(err)
+		is.Equal(info(i, p.Simplify(test.precise, test.th...).String()), info(i, test.want))
+	}
+}
+
+func TestFormatWeeks(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input       string
+		weeks       string
+		withoutWeek string
+	}{
+		{"P0D", "0 days", "0 days"},
+		{"P1D", "1 day", "1 day"},
+		{"P7D", "1 week", "7 days"},
+		{"P10D", "1 week, 3 days", "10 days"},
+		{"P70D", "10 weeks", "70 days"},
+		{"P71D", "10 weeks, 1 day", "71 days"},
+		{"P2W", "2 weeks", "14 days"},
+		{"P2W8D", "3 weeks, 1 day", "22 days"},
+		{"P1Y2W", "1 year, 2 weeks", "1 year, 14 days"},
+	}
+
+	for i, test := range tests {
+		p, err := period.ParseWithOptions(test.input, period.KeepWeeks)
+		is.NoErr(err)
+		is.Equal(info(i, p.Format()), info(i, test.weeks))
+		is.Equal(info(i, p.FormatWithoutWeeks()), info(i, test.withoutWeek))
+	}
+
+	// Day counts that are multiples of 7 but not of 70 were once mis-written
+	for days := int64(1); days <= 200; days++ {
+		p := period.NewYMD(0, 0, days)
+		is.Equal(p.String(), fmt.Sprintf("P%dD", days))
+	}
+}
+
+func TestAppendString(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"P0D", "P0D"},
+		{"PT1.05S", "PT1.05S"},
+		{"PT0.5S", "PT0.5S"},
+		{"P1Y2M3DT4H5M6S", "P1Y2M3DT4H5M6S"},
+		{"-P1Y2M", "-P1Y2M"},
+	}
+
+	for _, test := range tests {
+		p, err := period.Parse(test.input)
+		is.NoErr(err)
+		is.Equal(string(p.AppendString(nil)), test.want)
+		is.Equal(p.String(), test.want)
+	}
+
+	p := period.MustParse("P1DT2H", false)
+	buf := xfmt.Buffer{}
+	buf.S("every ").V(&p).S(" or so")
+	is.Equal(string(buf.Bytes()), "every P1DT2H or so")
+}
+
 // No use of arbitrary precision decimals
 // With 'I', 13, 575
 // 15.77 ns/op   0 B/op   0 allocs/op
@@ -229,25 +537,66 @@ func TestGetFractionalParts(t *testing.T) {
 		part rune
 		pre  int64
 		post float64
+		want [7]int64
 	}
 
 	parts := []periodParts{
-		{'S', 1, .1},           // 1.1 seconds - should give 100 ms
-		{'S', 13, .1575},       // 13.1575 seconds - should give 157 ms
-		{'I', 13, .575},        // 13.575 minutes - should give 575 ms
-		{'H', 200, .5},         // 200 hours and 30 minutes
-		{'Y', 260, .5},         // 260 years and 6 months
-		{'W', 260, .5},         // 260 weeks and .5 week
-		{'Y', 15000000000, .5}, // 15 billion years (and 6 months) - over threshold
+		{'S', 1, .1, [7]int64{0, 0, 0, 0, 0, 1, 100000000}},             // 1.1 seconds - should give 100 ms
+		{'S', 13, .1575, [7]int64{0, 0, 0, 0, 0, 13, 157500000}},        // 13.1575 seconds - should give 157.5 ms
+		{'S', 0, .000001, [7]int64{0, 0, 0, 0, 0, 0, 1000}},             // one microsecond
+		{'S', 0, .000000001, [7]int64{0, 0, 0, 0, 0, 0, 1}},             // one nanosecond
+		{'I', 13, .575, [7]int64{0, 0, 0, 0, 13, 34, 500000000}},        // 13.575 minutes - should give 34.5 seconds
+		{'H', 200, .5, [7]int64{0, 0, 0, 200, 30, 0, 0}},                // 200 hours and 30 minutes
+		{'Y', 260, .5, [7]int64{260, 6, 0, 0, 0, 0, 0}},                 // 260 years and 6 months
+		{'W', 260, .5, [7]int64{0, 0, 1823, 12, 0, 0, 0}},               // 260 weeks and .5 week
+		{'Y', 15000000000, .5, [7]int64{15000000000, 6, 0, 0, 0, 0, 0}}, // 15 billion years (and 6 months) - over threshold
+		{'Y', 30000, 575, [7]int64{30575, 0, 0, 0, 0, 0, 0}},            // a fractional value of one or more adds to the whole
 	}
 
 	p := message.NewPrinter(message.MatchLanguage("en"))
 
-	for _, part := range parts {
+	for i, part := range parts {
 		years, months, days, hours, minutes, seconds, subseconds, err := period.AdditionsFromDecimalSection(part.part, part.pre, part.post)
 		is.NoErr(err)
 		t.Log(p.Sprintf("part %s pre %d post %f years %d, months %d, days %d, hours %d, minutes %d, seconds %d, subseconds %d, err %v",
 			string(part.part), part.pre, part.post, years, months, days, hours, minutes, seconds, subseconds, err))
+		is.Equal(info(i, [7]int64{years, months, days, hours, minutes, seconds, subseconds}), info(i, part.want))
+	}
+
+	_, _, _, _, _, _, _, err := period.AdditionsFromDecimalSection('X', 1, .5)
+	is.True(err != nil) // not a period part
+}
+
+func TestNewOf(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		duration time.Duration
+		want     string
+		precise  bool
+	}{
+		{0, "P0D", true},
+		{time.Nanosecond, "PT0.000000001S", true},
+		{time.Microsecond, "PT0.000001S", true},
+		{90*time.Minute + time.Nanosecond, "PT1H30M0.000000001S", true},
+		{-90 * time.Minute, "-PT1H30M", true},
+		{3276 * time.Hour, "PT3276H", true},
+		{-5000*time.Hour - 3, "-P208DT8H0.000000003S", false},
+		{math.MaxInt64, "P292Y3M8DT23H47M16.854775807S", false},
+		{math.MinInt64, "-P292Y3M8DT23H47M16.854775808S", false},
+	}
+
+	for i, test := range tests {
+		p, precise := period.NewOf(test.duration)
+		is.Equal(info(i, p.String()), info(i, test.want))
+		is.Equal(info(i, precise), info(i, test.precise))
+
+		// Durations within HMS range convert back exactly
+		if precise {
+			d, _, err := p.Duration()
+			is.NoErr(err)
+			is.Equal(info(i, d), info(i, test.duration))
+		}
 	}
 }
 
@@ -393,3 +742,22 @@ func BenchmarkParsePeriodShort(b *testing.B) {
 	is.True(p != period.Period{})
 	is.NoErr(err) // Parsing should not have caused an error
 }
+
+// Appending into a reused buffer should not allocate
+func BenchmarkAppendString(b *testing.B) {
+	is := is.New(b)
+
+	p, err := period.Parse("P1Y2M3DT4H5M6.5S")
+	is.NoErr(err)
+
+	buf := make([]byte, 0, 64)
+
+	b.ResetTimer()
+	b.SetBytes(bechmarkBytesPerOp)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = p.AppendString(buf[:0])
+	}
+
+	is.True(len(buf) > 0)
+}
@@ -0,0 +1,278 @@
+package period
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/imarsman/datetime/timestamp"
+)
+
+// Unbounded is the number of repetitions of a Recurrence that repeats without
+// limit, written as "R" or "R-1".
+const Unbounded = -1
+
+// RecurrenceForm selects which of the ISO-8601 repeating interval forms a
+// Recurrence has.
+type RecurrenceForm int
+
+const (
+	// StartPeriod recurrences are written as "Rn/start/period" and repeat
+	// forwards from the start.
+	StartPeriod RecurrenceForm = iota
+	// PeriodEnd recurrences are written as "Rn/period/end" and repeat
+	// backwards from the end.
+	PeriodEnd
+	// StartEnd recurrences are written as "Rn/start/end" and repeat forwards
+	// from the start, using the period between the start and end.
+	StartEnd
+)
+
+// Recurrence is an ISO-8601 repeating interval, such as
+// "R5/2021-03-01T13:00:00Z/P1Y2M10DT2H30M", which is five intervals of the
+// period, starting at the given time.
+type Recurrence struct {
+	// Repetitions is the number of intervals, or Unbounded.
+	Repetitions int64
+	// Form selects which of Start, End and Period are given.
+	Form RecurrenceForm
+	// Start is the start of the first interval, in the StartPeriod and
+	// StartEnd forms.
+	Start time.Time
+	// End is the end of the last interval in the PeriodEnd form, or of the
+	// first interval in the StartEnd form.
+	End time.Time
+	// Period is the length of each interval. In the StartEnd form it is set by
+	// ParseRecurrence to the period between the start and end.
+	Period Period
+}
+
+// ParseRecurrence parses an ISO-8601 repeating interval in any of its forms:
+//
+//	R5/2021-03-01T13:00:00Z/P1Y2M10DT2H30M   start and period
+//	R/P1D/2021-12-31                         period and end
+//	R2/2021-03-01T13:00:00Z/2021-03-02T13:00:00Z   start and end
+//
+// The count after "R" is the number of intervals; if it is blank or -1 the
+// recurrence is unbounded. Times are parsed as with
+// timestamp.ParseISOTimestamp, using the location for times without a zone
+// offset. Periods keep any weeks, and must be positive.
+func ParseRecurrence(value string, location *time.Location) (Recurrence, error) {
+	fail := func(reason string) (Recurrence, error) {
+		return Recurrence{}, fmt.Errorf("period.ParseRecurrence: cannot parse %q: %s", value, reason)
+	}
+
+	parts := strings.Split(value, "/")
+	if len(parts) != 3 || !strings.HasPrefix(parts[0], "R") {
+		return fail("expected Rn/start/period, Rn/period/end or Rn/start/end")
+	}
+
+	var r Recurrence
+	switch count := parts[0][1:]; count {
+	case "", "-1":
+		r.Repetitions = Unbounded
+	default:
+		n, err := strconv.ParseInt(count, 10, 64)
+		if err != nil || n < 0 || strings.HasPrefix(count, "+") {
+			return fail("bad number of repetitions")
+		}
+		r.Repetitions = n
+	}
+
+	isPeriod := func(s string) bool {
+		return strings.HasPrefix(s, "P")
+	}
+	parsePeriod := func(s string) (Period, error) {
+		p, err := ParseWithOptions(s, KeepWeeks)
+		if err != nil {
+			return Period{}, err
+		}
+		if p.IsZero() || p.IsNegative() {
+			return Period{}, errors.New("the period must be positive")
+		}
+		return p, nil
+	}
+	parseTime := func(s string) (time.Time, error) {
+		return timestamp.ParseISOTimestamp(s, location)
+	}
+
+	var err error
+	switch {
+	case isPeriod(parts[1]) && isPeriod(parts[2]):
+		return fail("it has no start or end")
+
+	case isPeriod(parts[1]):
+		r.Form = PeriodEnd
+		if r.Period, err = parsePeriod(parts[1]); err != nil {
+			return fail(err.Error())
+		}
+		if r.End, err = parseTime(parts[2]); err != nil {
+			return fail(err.Error())
+		}
+
+	case isPeriod(parts[2]):
+		r.Form = StartPeriod
+		if r.Start, err = parseTime(parts[1]); err != nil {
+			return fail(err.Error())
+		}
+		if r.Period, err = parsePeriod(parts[2]); err != nil {
+			return fail(err.Error())
+		}
+
+	default:
+		r.Form = StartEnd
+		if r.Start, err = parseTime(parts[1]); err != nil {
+			return fail(err.Error())
+		}
+		if r.End, err = parseTime(parts[2]); err != nil {
+			return fail(err.Error())
+		}
+		if !r.End.After(r.Start) {
+			return fail("the end must be after the start")
+		}
+		r.Period = Between(r.Start, r.End)
+	}
+
+	return r, nil
+}
+
+// String gives the recurrence in ISO-8601 form, such as
+// "R5/2021-03-01T13:00:00Z/P1Y2M10DT2H30M". Times are written as with
+// time.RFC3339Nano.
+func (r Recurrence) String() string {
+	var b strings.Builder
+	b.WriteByte('R')
+	if r.Repetitions != Unbounded {
+		b.WriteString(strconv.FormatInt(r.Repetitions, 10))
+	}
+	b.WriteByte('/')
+
+	p := r.Period
+	switch r.Form {
+	case PeriodEnd:
+		b.WriteString(p.String())
+		b.WriteByte('/')
+		b.WriteString(r.End.Format(time.RFC3339Nano))
+	case StartEnd:
+		b.WriteString(r.Start.Format(time.RFC3339Nano))
+		b.WriteByte('/')
+		b.WriteString(r.End.Format(time.RFC3339Nano))
+	default:
+		b.WriteString(r.Start.Format(time.RFC3339Nano))
+		b.WriteByte('/')
+		b.WriteString(p.String())
+	}
+
+	return b.String()
+}
+
+// Occurrences gives an iterator over the intervals of the recurrence in the
+// location, as with OccurrencesWithEndOfMonth using EndOfMonthOverflow.
+func (r Recurrence) Occurrences(location *time.Location) *RecurrenceIterator {
+	return r.OccurrencesWithEndOfMonth(location, EndOfMonthOverflow)
+}
+
+// OccurrencesWithEndOfMonth gives an iterator over the intervals of the
+// recurrence in the location. The nth interval starts at the start plus n
+// times the period, added with AddToWithEndOfMonth in the location, so that
+// months do not drift and daily recurrences keep their clock time across
+// daylight saving changes. In the StartEnd form the period is found again with
+// Between in the location.
+//
+// Bounded PeriodEnd recurrences give their intervals from the earliest, but
+// unbounded ones can only be counted back from the end, so give them from the
+// latest.
+func (r Recurrence) OccurrencesWithEndOfMonth(location *time.Location, eom EndOfMonth) *RecurrenceIterator {
+	it := &RecurrenceIterator{r: r, eom: eom}
+
+	switch r.Form {
+	case PeriodEnd:
+		it.anchor = r.End.In(location)
+		it.backwards = true
+	case StartEnd:
+		it.anchor = r.Start.In(location)
+		it.r.Period = Between(it.anchor, r.End.In(location))
+	default:
+		it.anchor = r.Start.In(location)
+	}
+
+	return it
+}
+
+// RecurrenceIterator gives the intervals of a Recurrence in turn. Call Next to
+// move to each interval before using Start and End:
+//
+//	it := r.Occurrences(location)
+//	for it.Next() {
+//		schedule(it.Start(), it.End())
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type RecurrenceIterator struct {
+	r          Recurrence
+	eom        EndOfMonth
+	anchor     time.Time
+	backwards  bool
+	n          int64
+	start, end time.Time
+	err        error
+}
+
+// Next moves to the next interval, returning false when there are no more or
+// an error occurred.
+func (it *RecurrenceIterator) Next() bool {
+	if it.err != nil || (it.r.Repetitions != Unbounded && it.n >= it.r.Repetitions) || it.n == math.MaxInt64 {
+		return false
+	}
+
+	// Offsets from the anchor, in periods, of the ends of the interval
+	from, to := it.n, it.n+1
+	if it.backwards {
+		if it.r.Repetitions == Unbounded {
+			from, to = -(it.n + 1), -it.n
+		} else {
+			from, to = it.n-it.r.Repetitions, it.n-it.r.Repetitions+1
+		}
+	}
+
+	if it.start, it.err = it.at(from); it.err != nil {
+		return false
+	}
+	if it.end, it.err = it.at(to); it.err != nil {
+		return false
+	}
+	it.n++
+
+	return true
+}
+
+// at gives the anchor plus n periods.
+func (it *RecurrenceIterator) at(n int64) (time.Time, error) {
+	offset, err := it.r.Period.Multiply(n)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, _, err := offset.AddToWithEndOfMonth(it.anchor, it.eom)
+
+	return t, err
+}
+
+// Start gives the start of the current interval.
+func (it *RecurrenceIterator) Start() time.Time {
+	return it.start
+}
+
+// End gives the end of the current interval.
+func (it *RecurrenceIterator) End() time.Time {
+	return it.end
+}
+
+// Err gives the error, if any, that stopped the iteration, such as a time
+// that overflows.
+func (it *RecurrenceIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,113 @@
+package period_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+func TestParseRecurrence(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		value       string
+		repetitions int64
+		form        period.RecurrenceForm
+		period      string
+		want        string
+	}{
+		{"R5/2021-03-01T13:00:00Z/P1Y2M10DT2H30M", 5, period.StartPeriod, "P1Y2M10DT2H30M", "R5/2021-03-01T13:00:00Z/P1Y2M10DT2H30M"},
+		{"R/P1D/2021-12-31", period.Unbounded, period.PeriodEnd, "P1D", "R/P1D/2021-12-31T00:00:00Z"},
+		{"R-1/2021-03-01T13:00:00Z/P1W", period.Unbounded, period.StartPeriod, "P1W", "R/2021-03-01T13:00:00Z/P1W"},
+		{"R0/2021-03-01T13:00:00Z/PT1H", 0, period.StartPeriod, "PT1H", "R0/2021-03-01T13:00:00Z/PT1H"},
+		{"R2/2021-03-01T13:00:00Z/2021-04-02T14:00:00Z", 2, period.StartEnd, "P1M1DT1H", "R2/2021-03-01T13:00:00Z/2021-04-02T14:00:00Z"},
+		{"R3/2021-03-01T13:00:00.5-05:00/PT0.5S", 3, period.StartPeriod, "PT0.5S", "R3/2021-03-01T13:00:00.5-05:00/PT0.5S"},
+	}
+
+	for i, test := range tests {
+		r, err := period.ParseRecurrence(test.value, time.UTC)
+		is.NoErr(err)
+		is.Equal(info(i, r.Repetitions), info(i, test.repetitions))
+		is.Equal(info(i, r.Form), info(i, test.form))
+		is.Equal(info(i, r.Period.String()), info(i, test.period))
+		is.Equal(info(i, r.String()), info(i, test.want))
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	is := is.New(t)
+
+	for _, value := range []string{
+		"", "R5", "R5/P1D", "5/2021-03-01/P1D", "Rx/2021-03-01/P1D", "R-2/2021-03-01/P1D",
+		"R5/P1D/P1D", "R5/2021-03-01/2021-02-01", "R5/2021-03-01/P0D", "R5/2021-03-01/-P1D",
+		"R5/not-a-time/P1D", "R5/2021-03-01/P1X", "R5/2021-03-01/P1D/P1D",
+	} {
+		_, err := period.ParseRecurrence(value, time.UTC)
+		is.True(err != nil) // value should be rejected
+	}
+}
+
+func TestRecurrenceOccurrences(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	starts := func(r period.Recurrence, location *time.Location, eom period.EndOfMonth, limit int) []string {
+		var got []string
+		it := r.OccurrencesWithEndOfMonth(location, eom)
+		for len(got) < limit && it.Next() {
+			got = append(got, it.Start().Format(time.RFC3339))
+		}
+		is.NoErr(it.Err())
+		return got
+	}
+
+	// daily at 13:00 in Toronto, across the change to daylight saving time
+	r, err := period.ParseRecurrence("R3/2021-03-13T13:00:00-05:00/P1D", time.UTC)
+	is.NoErr(err)
+	is.Equal(starts(r, toronto, period.EndOfMonthOverflow, 10), []string{
+		"2021-03-13T13:00:00-05:00", "2021-03-14T13:00:00-04:00", "2021-03-15T13:00:00-04:00",
+	})
+
+	// monthly from the end of a month does not drift
+	r, err = period.ParseRecurrence("R/2021-01-31T00:00:00Z/P1M", time.UTC)
+	is.NoErr(err)
+	is.Equal(starts(r, time.UTC, period.EndOfMonthClamp, 4), []string{
+		"2021-01-31T00:00:00Z", "2021-02-28T00:00:00Z", "2021-03-31T00:00:00Z", "2021-04-30T00:00:00Z",
+	})
+	is.Equal(starts(r, time.UTC, period.EndOfMonthOverflow, 3), []string{
+		"2021-01-31T00:00:00Z", "2021-03-03T00:00:00Z", "2021-03-31T00:00:00Z",
+	})
+
+	// bounded period/end is given from the earliest
+	r, err = period.ParseRecurrence("R3/P1D/2021-12-31", time.UTC)
+	is.NoErr(err)
+	is.Equal(starts(r, time.UTC, period.EndOfMonthOverflow, 10), []string{
+		"2021-12-28T00:00:00Z", "2021-12-29T00:00:00Z", "2021-12-30T00:00:00Z",
+	})
+
+	// unbounded period/end is given from the latest
+	r, err = period.ParseRecurrence("R/P1D/2021-12-31", time.UTC)
+	is.NoErr(err)
+	is.Equal(starts(r, time.UTC, period.EndOfMonthOverflow, 2), []string{
+		"2021-12-30T00:00:00Z", "2021-12-29T00:00:00Z",
+	})
+
+	// start/end repeats the period between them
+	r, err = period.ParseRecurrence("R2/2021-01-15T00:00:00Z/2021-02-15T00:00:00Z", time.UTC)
+	is.NoErr(err)
+	it := r.Occurrences(time.UTC)
+	is.True(it.Next())
+	is.True(it.Next())
+	is.Equal(it.Start(), time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC))
+	is.Equal(it.End(), time.Date(2021, 3, 15, 0, 0, 0, 0, time.UTC))
+	is.True(!it.Next())
+
+	// no repetitions
+	r, err = period.ParseRecurrence("R0/2021-01-15T00:00:00Z/P1D", time.UTC)
+	is.NoErr(err)
+	is.True(!r.Occurrences(time.UTC).Next())
+}
@@ -0,0 +1,284 @@
+package period
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/rickb777/plural"
+)
+
+// RelativeNames holds the localisable phrases used when describing an instant
+// relative to the current time. The unit names are plurals as used by
+// FormatWithPeriodNames. The Past and Future phrases must contain a single %s
+// placeholder for the units, e.g. "%s ago". The calendar phrases take the
+// formatted time of day and, for the weekday phrases, the weekday name first.
+type RelativeNames struct {
+	Years, Months, Weeks, Days, Hours, Minutes, Seconds plural.Plurals
+
+	Past   string // e.g. "%s ago"
+	Future string // e.g. "in %s"
+	Now    string // used when the instant is within the JustNow threshold
+
+	Yesterday   string // e.g. "yesterday at %s"
+	Tomorrow    string // e.g. "tomorrow at %s"
+	LastWeekday string // e.g. "last %s at %s"
+	NextWeekday string // e.g. "%s at %s"
+
+	Weekdays   [7]string // indexed by time.Weekday
+	Separator  string    // used between units, e.g. ", "
+	TimeLayout string    // layout for the time of day, e.g. "15:04"
+}
+
+// RelativeEnglishNames provides the English default phrases for relative
+// formatting.
+var RelativeEnglishNames = RelativeNames{
+	Years:   PeriodYearNames,
+	Months:  PeriodMonthNames,
+	Weeks:   PeriodWeekNames,
+	Days:    plural.FromZero("", "%v day", "%v days"),
+	Hours:   PeriodHourNames,
+	Minutes: PeriodMinuteNames,
+	Seconds: PeriodSecondNames,
+
+	Past:   "%s ago",
+	Future: "in %s",
+	Now:    "just now",
+
+	Yesterday:   "yesterday at %s",
+	Tomorrow:    "tomorrow at %s",
+	LastWeekday: "last %s at %s",
+	NextWeekday: "%s at %s",
+
+	Weekdays: [7]string{
+		"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday",
+	},
+	Separator:  ", ",
+	TimeLayout: "15:04",
+}
+
+// RelativeThresholds defines when the next larger unit is used. For example
+// with a Minutes threshold of 45, a difference of 45 minutes or more is
+// described in hours. Values are compared after rounding to the unit.
+type RelativeThresholds struct {
+	JustNow time.Duration // differences below this use the Now phrase
+	Seconds int64         // seconds before minutes are used
+	Minutes int64         // minutes before hours are used
+	Hours   int64         // hours before days are used
+	Days    int64         // days before months are used
+	Months  int64         // months before years are used
+}
+
+// DefaultRelativeThresholds are the thresholds used by NewRelativeFormatter.
+var DefaultRelativeThresholds = RelativeThresholds{
+	JustNow: 10 * time.Second,
+	Seconds: 45,
+	Minutes: 45,
+	Hours:   22,
+	Days:    26,
+	Months:  11,
+}
+
+// RelativeFormatter describes instants relative to a reference clock, such as
+// "3 hours ago", "in 2 days" or "tomorrow at 15:00".
+//
+// Months and years are measured using the Gregorian averages of 30.436875 and
+// 365.2425 days. Differences beyond the range of time.Duration (about 292
+// years) have most of their years counted in the calendar instead.
+type RelativeFormatter struct {
+	// Clock supplies the reference instant. If nil, time.Now is used.
+	Clock func() time.Time
+	// Location is used for calendar wording. If nil, the location of the
+	// reference instant is used.
+	Location *time.Location
+	// MaxUnits is the largest number of units shown, e.g. 2 gives
+	// "2 hours, 3 minutes ago". Values below 1 are treated as 1.
+	MaxUnits int
+	// Weeks enables weeks for differences of at least seven days.
+	Weeks bool
+	// Calendar enables phrases such as "yesterday at 15:00" and
+	// "last Friday at 09:30" for instants within six calendar days.
+	Calendar   bool
+	Thresholds RelativeThresholds
+	Names      RelativeNames
+}
+
+// NewRelativeFormatter creates a relative formatter using the supplied clock,
+// the English names and the default thresholds. A nil clock means time.Now.
+func NewRelativeFormatter(clock func() time.Time) RelativeFormatter {
+	return RelativeFormatter{
+		Clock:      clock,
+		MaxUnits:   1,
+		Thresholds: DefaultRelativeThresholds,
+		Names:      RelativeEnglishNames,
+	}
+}
+
+// relative unit sizes in nanoseconds
+const (
+	relSecond = int64(time.Second)
+	relMinute = int64(time.Minute)
+	relHour   = int64(time.Hour)
+	relDay    = 24 * relHour
+	relWeek   = 7 * relDay
+	relMonth  = daysPerMonthE6 * (relDay / oneE6)
+	relYear   = daysPerYearE4 * (relDay / oneE4)
+)
+
+// maxRelative is the largest difference measured only in nanoseconds, which
+// leaves room to round to the largest unit without overflowing.
+const maxRelative = time.Duration(math.MaxInt64 - relYear)
+
+// relativeKeptYears is the number of years of a larger difference that are
+// measured in nanoseconds along with the rest of it.
+const relativeKeptYears = 100
+
+// Format describes t relative to the formatter's clock.
+func (f RelativeFormatter) Format(t time.Time) string {
+	now := time.Now()
+	if f.Clock != nil {
+		now = f.Clock()
+	}
+
+	location := f.Location
+	if location == nil {
+		location = now.Location()
+	}
+	now = now.In(location)
+	t = t.In(location)
+
+	d := t.Sub(now)
+	future := d > 0
+
+	// Sub saturates beyond the range of time.Duration, so for large gaps most
+	// of the whole years are counted in the calendar, leaving a remainder of
+	// about a century that is measured in nanoseconds
+	var extraYears int64
+	if d > maxRelative || d < -maxRelative {
+		years := BetweenUnits(now, t, Year).Years()
+		if future {
+			years -= relativeKeptYears
+		} else {
+			years += relativeKeptYears
+		}
+		d = t.Sub(now.AddDate(int(years), 0, 0))
+		extraYears = absInt64(years)
+	}
+	if d < 0 {
+		d = -d
+	}
+
+	// Differences that round to zero seconds are always now
+	if d < f.Thresholds.JustNow || d < time.Second/2 {
+		return f.Names.Now
+	}
+
+	if f.Calendar {
+		if s, ok := f.calendarPhrase(now, t); ok {
+			return s
+		}
+	}
+
+	units := f.units(int64(d), extraYears)
+	if future {
+		return fmt.Sprintf(f.Names.Future, units)
+	}
+	return fmt.Sprintf(f.Names.Past, units)
+}
+
+// calendarPhrase gives calendar wording for instants on a different day
+// within six days of now.
+func (f RelativeFormatter) calendarPhrase(now, t time.Time) (string, bool) {
+	y1, m1, d1 := now.Date()
+	y2, m2, d2 := t.Date()
+	dayDiff := int(time.Date(y2, m2, d2, 0, 0, 0, 0, time.UTC).Sub(
+		time.Date(y1, m1, d1, 0, 0, 0, 0, time.UTC)) / (24 * time.Hour))
+
+	clock := t.Format(f.Names.TimeLayout)
+	weekday := f.Names.Weekdays[t.Weekday()]
+
+	switch {
+	case dayDiff == -1:
+		return fmt.Sprintf(f.Names.Yesterday, clock), true
+	case dayDiff == 1:
+		return fmt.Sprintf(f.Names.Tomorrow, clock), true
+	case dayDiff < -1 && dayDiff > -7:
+		return fmt.Sprintf(f.Names.LastWeekday, weekday, clock), true
+	case dayDiff > 1 && dayDiff < 7:
+		return fmt.Sprintf(f.Names.NextWeekday, weekday, clock), true
+	}
+
+	return "", false
+}
+
+// units formats an absolute difference in nanoseconds plus a number of extra
+// years using the largest unit allowed by the thresholds followed by up to
+// MaxUnits-1 smaller units. The smallest unit shown is rounded half up.
+func (f RelativeFormatter) units(d int64, extraYears int64) string {
+	sizes := []int64{relYear, relMonth, relWeek, relDay, relHour, relMinute, relSecond}
+	names := []plural.Plurals{
+		f.Names.Years, f.Names.Months, f.Names.Weeks, f.Names.Days,
+		f.Names.Hours, f.Names.Minutes, f.Names.Seconds,
+	}
+	const weekIndex = 2
+
+	rounded := func(size int64) int64 {
+		return (d + size/2) / size
+	}
+
+	th := f.Thresholds
+	var start int
+	switch {
+	case rounded(relSecond) < th.Seconds:
+		start = 6
+	case rounded(relMinute) < th.Minutes:
+		start = 5
+	case rounded(relHour) < th.Hours:
+		start = 4
+	case rounded(relDay) < th.Days:
+		start = 3
+		if f.Weeks && rounded(relDay) >= 7 {
+			start = weekIndex
+		}
+	case rounded(relMonth) < th.Months:
+		start = 1
+	default:
+		start = 0
+	}
+	if extraYears > 0 {
+		start = 0
+	}
+
+	// Gather the indexes of the units that may be shown
+	maxUnits := f.MaxUnits
+	if maxUnits < 1 {
+		maxUnits = 1
+	}
+	shown := make([]int, 0, maxUnits)
+	for i := start; i < len(sizes) && len(shown) < maxUnits; i++ {
+		if i == weekIndex && !f.Weeks {
+			continue
+		}
+		shown = append(shown, i)
+	}
+
+	// Round the whole difference to the smallest unit shown so that carries
+	// into larger units are handled by the decomposition below.
+	smallest := sizes[shown[len(shown)-1]]
+	rem := rounded(smallest) * smallest
+
+	parts := make([]string, 0, len(shown))
+	for _, i := range shown {
+		n := rem / sizes[i]
+		rem -= n * sizes[i]
+		if i == 0 {
+			n += extraYears
+		}
+		if n == 0 {
+			continue
+		}
+		parts = appendNonBlank(parts, names[i].FormatInt(int(n)))
+	}
+	return strings.Join(parts, f.Names.Separator)
+}
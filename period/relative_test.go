@@ -0,0 +1,149 @@
+package period_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+	"github.com/rickb777/plural"
+)
+
+// Wednesday 2021-03-17 12:00 UTC
+var relativeNow = time.Date(2021, 3, 17, 12, 0, 0, 0, time.UTC)
+
+func relativeClock() time.Time {
+	return relativeNow
+}
+
+func TestRelativeFormat(t *testing.T) {
+	is := is.New(t)
+
+	f := period.NewRelativeFormatter(relativeClock)
+
+	tests := []struct {
+		offset time.Duration
+		want   string
+	}{
+		{0, "just now"},
+		{-5 * time.Second, "just now"},
+		{-30 * time.Second, "30 seconds ago"},
+		{30 * time.Second, "in 30 seconds"},
+		{-50 * time.Second, "1 minute ago"},
+		{-3 * time.Hour, "3 hours ago"},
+		{-3*time.Hour - 40*time.Minute, "4 hours ago"},
+		{-44 * time.Minute, "44 minutes ago"},
+		{-45 * time.Minute, "1 hour ago"},
+		{2 * 24 * time.Hour, "in 2 days"},
+		{-23 * time.Hour, "1 day ago"},
+		{-40 * 24 * time.Hour, "1 month ago"},
+		{-400 * 24 * time.Hour, "1 year ago"},
+		{-330 * 24 * time.Hour, "1 year ago"},
+	}
+
+	for i, test := range tests {
+		got := f.Format(relativeNow.Add(test.offset))
+		is.Equal(info(i, got), info(i, test.want))
+	}
+}
+
+func TestRelativeFormatBeyondDuration(t *testing.T) {
+	is := is.New(t)
+
+	f := period.NewRelativeFormatter(relativeClock)
+
+	tests := []struct {
+		years, months int
+		want          string
+	}{
+		{-500, 0, "500 years ago"},
+		{500, 0, "in 500 years"},
+		{-292, 0, "292 years ago"},
+		{292, 0, "in 292 years"},
+		{-100, 0, "100 years ago"},
+		{-10000, 0, "10000 years ago"},
+		{-500, -7, "501 years ago"},
+		{500, 5, "in 500 years"},
+	}
+
+	for i, test := range tests {
+		got := f.Format(relativeNow.AddDate(test.years, test.months, 0))
+		is.Equal(info(i, got), info(i, test.want))
+	}
+
+	f.MaxUnits = 2
+	is.Equal(f.Format(relativeNow.AddDate(-500, -3, 0)), "500 years, 3 months ago")
+	is.Equal(f.Format(relativeNow.AddDate(1000, 2, 0)), "in 1000 years, 2 months")
+}
+
+func TestRelativeFormatUnits(t *testing.T) {
+	is := is.New(t)
+
+	f := period.NewRelativeFormatter(relativeClock)
+	f.MaxUnits = 2
+
+	tests := []struct {
+		offset time.Duration
+		want   string
+	}{
+		{-(2*time.Hour + 3*time.Minute), "2 hours, 3 minutes ago"},
+		{2*time.Hour + 3*time.Minute + 40*time.Second, "in 2 hours, 4 minutes"},
+		{-(2*time.Hour + 20*time.Second), "2 hours ago"},
+		{-(time.Hour + 59*time.Minute + 50*time.Second), "2 hours ago"},
+		{-(3*24*time.Hour + 5*time.Hour), "3 days, 5 hours ago"},
+	}
+
+	for i, test := range tests {
+		got := f.Format(relativeNow.Add(test.offset))
+		is.Equal(info(i, got), info(i, test.want))
+	}
+
+	f.MaxUnits = 1
+	f.Weeks = true
+	is.Equal(f.Format(relativeNow.Add(-15*24*time.Hour)), "2 weeks ago")
+
+	f.Thresholds.Hours = 48
+	is.Equal(f.Format(relativeNow.Add(-30*time.Hour)), "30 hours ago")
+}
+
+func TestRelativeFormatCalendar(t *testing.T) {
+	is := is.New(t)
+
+	f := period.NewRelativeFormatter(relativeClock)
+	f.Calendar = true
+
+	tests := []struct {
+		t    time.Time
+		want string
+	}{
+		{time.Date(2021, 3, 17, 9, 0, 0, 0, time.UTC), "3 hours ago"},
+		{time.Date(2021, 3, 18, 15, 0, 0, 0, time.UTC), "tomorrow at 15:00"},
+		{time.Date(2021, 3, 16, 23, 30, 0, 0, time.UTC), "yesterday at 23:30"},
+		{time.Date(2021, 3, 12, 9, 30, 0, 0, time.UTC), "last Friday at 09:30"},
+		{time.Date(2021, 3, 20, 8, 0, 0, 0, time.UTC), "Saturday at 08:00"},
+		{time.Date(2021, 3, 1, 8, 0, 0, 0, time.UTC), "16 days ago"},
+	}
+
+	for i, test := range tests {
+		got := f.Format(test.t)
+		is.Equal(info(i, got), info(i, test.want))
+	}
+
+	// Calendar days are taken in the formatter's location
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	is.NoErr(err)
+	f.Location = tokyo
+	is.Equal(f.Format(time.Date(2021, 3, 17, 16, 0, 0, 0, time.UTC)), "tomorrow at 01:00")
+}
+
+func TestRelativeFormatLocalised(t *testing.T) {
+	is := is.New(t)
+
+	f := period.NewRelativeFormatter(relativeClock)
+	f.Names.Hours = plural.FromOne("%v heure", "%v heures")
+	f.Names.Past = "il y a %s"
+	f.Names.Future = "dans %s"
+
+	is.Equal(f.Format(relativeNow.Add(-3*time.Hour)), "il y a 3 heures")
+	is.Equal(f.Format(relativeNow.Add(time.Hour)), "dans 1 heure")
+}
@@ -0,0 +1,219 @@
+package period
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cockroachdb/apd"
+)
+
+// RoundingMode selects how Round treats the part of a period smaller than the
+// unit being rounded to.
+type RoundingMode int
+
+const (
+	// RoundHalfUp rounds to the nearest unit, with halves rounded away from
+	// zero, so PT1M30S rounds to PT2M and -PT1M30S to -PT2M.
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven rounds to the nearest unit, with halves rounded to an even
+	// number of units, so PT1M30S rounds to PT2M but PT2M30S also rounds to PT2M.
+	RoundHalfEven
+	// RoundFloor rounds towards negative infinity.
+	RoundFloor
+	// RoundCeil rounds towards positive infinity.
+	RoundCeil
+)
+
+// rounder gives the apd rounding for the mode.
+func (mode RoundingMode) rounder() string {
+	switch mode {
+	case RoundHalfEven:
+		return apd.RoundHalfEven
+	case RoundFloor:
+		return apd.RoundFloor
+	case RoundCeil:
+		return apd.RoundCeiling
+	}
+	return apd.RoundHalfUp
+}
+
+// roundField gives the index of the field for a single unit from Year to
+// Nanosecond, as with signedFields. Rounding to Day keeps any weeks.
+func roundField(unit Unit) (int, error) {
+	switch unit {
+	case Year:
+		return 0, nil
+	case Month:
+		return 1, nil
+	case Day:
+		return 3, nil
+	case Hour:
+		return 4, nil
+	case Minute:
+		return 5, nil
+	case Second:
+		return 6, nil
+	case Nanosecond:
+		return 7, nil
+	}
+	return 0, errors.New("period: rounding needs exactly one unit")
+}
+
+// Round rounds the period to a multiple of the unit, using the rounding mode.
+// Fields larger than the unit are kept as they are, and the smaller fields
+// are rounded into the field of the unit and cleared; e.g. PT1H29M45S rounded
+// to Minute is PT1H30M. Rounding to Day keeps any weeks.
+//
+// Where rounding makes a whole unit of a larger field, it is carried into
+// that field through the fixed ratios of seconds to minutes, minutes to hours
+// and months to years, so PT1H59M45S rounded to Minute is PT2H. Hours are not
+// carried into days, so P1DT23H45M rounded to Hour is P1DT24H; use Normalise
+// on the result if that is wanted.
+//
+// As the lengths of years, months and days vary, the part being rounded is
+// measured using a year of 365.2425 days, a month of 1/12 of that and days of
+// 24 hours, as with CompareApprox. Use RoundAt for calendar-exact rounding.
+//
+// An error is returned if unit is not a single unit or the result overflows.
+func (p Period) Round(unit Unit, mode RoundingMode) (Period, error) {
+	return p.round(unit, mode.rounder())
+}
+
+// Truncate rounds the period towards zero to a multiple of the unit, measuring
+// the part dropped as with Round; e.g. P1Y7M20D truncated to Month is P1Y7M
+// and PT90M truncated to Hour is PT1H.
+//
+// An error is returned if unit is not a single unit or the result overflows.
+func (p Period) Truncate(unit Unit) (Period, error) {
+	return p.round(unit, apd.RoundDown)
+}
+
+// round rounds the period as with Round, using the apd rounding.
+func (p Period) round(unit Unit, rounding string) (Period, error) {
+	index, err := roundField(unit)
+	if err != nil {
+		return Period{}, err
+	}
+
+	fields := p.signedFields()
+	remainder := weightedTotal(fields[index+1:], approxWeights[index+1:])
+	for i := index + 1; i < len(fields); i++ {
+		fields[i] = 0
+	}
+
+	return roundInto(fields, index, new(apd.Decimal), remainder, apd.New(approxWeights[index], 0), rounding)
+}
+
+// RoundAt rounds the period to a multiple of the unit, as with Round, but
+// measures the part being rounded in the calendar from the reference time, so
+// P1M15D rounded to Month is P2M from 1st January but P1M from 1st February.
+// Hours and smaller units are always elapsed time, so are rounded as with
+// Round.
+//
+// An error is returned if unit is not a single unit, the period cannot be
+// added to the reference time or the result overflows.
+func (p Period) RoundAt(unit Unit, mode RoundingMode, ref time.Time) (Period, error) {
+	return p.roundAt(unit, mode.rounder(), ref)
+}
+
+// TruncateAt truncates the period to a multiple of the unit, measuring the
+// part being dropped in the calendar from the reference time, so P1M30D
+// truncated to Month is P2M from 1st January but P1M from 1st July.
+//
+// An error is returned if unit is not a single unit or the period cannot be
+// added to the reference time.
+func (p Period) TruncateAt(unit Unit, ref time.Time) (Period, error) {
+	return p.roundAt(unit, apd.RoundDown, ref)
+}
+
+// roundAt rounds the period as with RoundAt, using the apd rounding.
+func (p Period) roundAt(unit Unit, rounding string, ref time.Time) (Period, error) {
+	index, err := roundField(unit)
+	if err != nil {
+		return Period{}, err
+	}
+	if unit&(Year|Month|Day) == 0 {
+		return p.round(unit, rounding)
+	}
+
+	fields := p.signedFields()
+	for i := index + 1; i < len(fields); i++ {
+		fields[i] = 0
+	}
+	kept, err := fromSignedFields(fields)
+	if err != nil {
+		return Period{}, err
+	}
+
+	start, _, err := kept.AddTo(ref)
+	if err != nil {
+		return Period{}, err
+	}
+	end, _, err := p.AddTo(ref)
+	if err != nil {
+		return Period{}, err
+	}
+	if end.Equal(start) {
+		return kept, nil
+	}
+
+	whole, remainder, length := calendarUnits(start, end, unit)
+
+	return roundInto(fields, index, apd.New(whole, 0), apd.New(int64(remainder), 0), apd.New(int64(length), 0), rounding)
+}
+
+// roundInto adds whole plus the quotient of remainder and size to the field
+// at index, rounding the total so that modes such as RoundHalfEven see the
+// whole value of the field.
+func roundInto(fields [8]int64, index int, whole, remainder, size *apd.Decimal, rounding string) (Period, error) {
+	ctx := apd.BaseContext.WithPrecision(100)
+	units := new(apd.Decimal)
+	ctx.Quo(units, remainder, size)
+	ctx.Add(units, units, whole)
+	ctx.Add(units, units, apd.New(fields[index], 0))
+
+	roundContext := *ctx
+	roundContext.Rounding = rounding
+	roundContext.RoundToIntegralValue(units, units)
+
+	n, err := units.Int64()
+	if err != nil {
+		return Period{}, errors.New("period: rounded value exceeds maximum")
+	}
+	fields, err = carryRounded(fields, index, fields[index], n)
+	if err != nil {
+		return Period{}, err
+	}
+
+	return fromSignedFields(fields)
+}
+
+// carryRatios gives the number of each field that make one of the field
+// before it, where that ratio is fixed. Hours are not carried into days, as
+// days may have more or fewer than 24 hours.
+var carryRatios = [8]int64{0, 12, 0, 0, 0, 60, 60, 1e9}
+
+// carryRounded sets the field at index from before to after its rounding and
+// carries any whole units the rounding made upward, so that PT1H59M45S
+// rounded to Minute is PT2H rather than PT1H60M. A field that was already
+// larger than the field before it, as in PT90M, is kept as it is.
+func carryRounded(fields [8]int64, index int, before, after int64) ([8]int64, error) {
+	fields[index] = after
+	for i := index; carryRatios[i] != 0; i-- {
+		ratio := carryRatios[i]
+		if absInt64(before) >= ratio || absInt64(fields[i]) < ratio {
+			break
+		}
+
+		carry := fields[i] / ratio
+		fields[i] -= carry * ratio
+		before = fields[i-1]
+		sum, err := sumOf(carry, 1, fields[i-1])
+		if err != nil {
+			return fields, errors.New("period: rounded value exceeds maximum")
+		}
+		fields[i-1] = sum
+	}
+
+	return fields, nil
+}
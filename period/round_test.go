@@ -0,0 +1,129 @@
+package period_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+func TestRound(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		period string
+		unit   period.Unit
+		mode   period.RoundingMode
+		want   string
+	}{
+		{"PT1H29M45S", period.Minute, period.RoundHalfUp, "PT1H30M"},
+		{"PT1H29M15S", period.Minute, period.RoundHalfUp, "PT1H29M"},
+		{"PT1M30S", period.Minute, period.RoundHalfUp, "PT2M"},
+		{"-PT1M30S", period.Minute, period.RoundHalfUp, "-PT2M"},
+		{"PT1M30S", period.Minute, period.RoundHalfEven, "PT2M"},
+		{"PT2M30S", period.Minute, period.RoundHalfEven, "PT2M"},
+		{"PT2M1S", period.Minute, period.RoundCeil, "PT3M"},
+		{"-PT2M1S", period.Minute, period.RoundCeil, "-PT2M"},
+		{"PT2M59S", period.Minute, period.RoundFloor, "PT2M"},
+		{"-PT2M1S", period.Minute, period.RoundFloor, "-PT3M"},
+		{"PT90M", period.Hour, period.RoundHalfUp, "PT2H"},
+		{"P1Y7M", period.Year, period.RoundHalfUp, "P2Y"},
+		{"P1Y5M", period.Year, period.RoundHalfUp, "P1Y"},
+		{"P1Y7M20D", period.Month, period.RoundHalfUp, "P1Y8M"},
+		{"P1W3DT13H", period.Day, period.RoundHalfUp, "P11D"},
+		{"PT1.5S", period.Second, period.RoundHalfEven, "PT2S"},
+		{"PT1.000000001S", period.Nanosecond, period.RoundHalfUp, "PT1.000000001S"},
+		// rounding carries into larger fields
+		{"PT1H59M45S", period.Minute, period.RoundHalfUp, "PT2H"},
+		{"-PT1H59M45S", period.Minute, period.RoundHalfUp, "-PT2H"},
+		{"PT59M59.9S", period.Second, period.RoundHalfUp, "PT1H"},
+		{"PT1M59.5S", period.Second, period.RoundHalfUp, "PT2M"},
+		{"P1Y11M20D", period.Month, period.RoundHalfUp, "P2Y"},
+		{"P11M20D", period.Month, period.RoundCeil, "P1Y"},
+		{"PT30M3600S", period.Minute, period.RoundHalfUp, "PT1H30M"},
+		// hours are not carried into days, and fields that were already large are kept
+		{"P1DT23H45M", period.Hour, period.RoundHalfUp, "P1DT24H"},
+		{"PT90M", period.Minute, period.RoundHalfUp, "PT90M"},
+		{"PT90M15S", period.Minute, period.RoundHalfUp, "PT90M"},
+	}
+
+	for i, test := range tests {
+		got, err := period.MustParse(test.period, false).Round(test.unit, test.mode)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, test.want))
+	}
+
+	_, err := period.MustParse("P1D", false).Round(period.Day|period.Hour, period.RoundHalfUp)
+	is.True(err != nil)
+}
+
+func TestTruncate(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		period string
+		unit   period.Unit
+		want   string
+	}{
+		{"P1Y7M20D", period.Month, "P1Y7M"},
+		{"PT1H29M45S", period.Minute, "PT1H29M"},
+		{"PT90M", period.Hour, "PT1H"},
+		{"-PT1H59M", period.Hour, "-PT1H"},
+		{"P1Y11M", period.Year, "P1Y"},
+	}
+
+	for i, test := range tests {
+		got, err := period.MustParse(test.period, false).Truncate(test.unit)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, test.want))
+	}
+}
+
+func TestRoundAt(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	jan := time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2021, time.February, 1, 0, 0, 0, 0, time.UTC)
+	jul := time.Date(2021, time.July, 1, 0, 0, 0, 0, time.UTC)
+	// the clocks go forward on 14th March 2021, so that day has 23 hours
+	dst := time.Date(2021, time.March, 13, 0, 0, 0, 0, toronto)
+
+	tests := []struct {
+		period string
+		unit   period.Unit
+		mode   period.RoundingMode
+		ref    time.Time
+		want   string
+	}{
+		{"P1M15D", period.Month, period.RoundHalfUp, jan, "P2M"},
+		{"P1M15D", period.Month, period.RoundHalfUp, feb, "P1M"},
+		{"P1M14D", period.Month, period.RoundHalfEven, jan, "P2M"},
+		{"P1Y6M", period.Year, period.RoundHalfUp, jan, "P1Y"},
+		{"P1Y7M", period.Year, period.RoundHalfUp, jan, "P2Y"},
+		{"-P1M15D", period.Month, period.RoundHalfUp, jan, "-P2M"},
+		{"-P1M15D", period.Month, period.RoundHalfUp, feb, "-P1M"},
+		{"P1DT11H45M", period.Day, period.RoundHalfUp, dst, "P2D"},
+		{"P1DT11H45M", period.Day, period.RoundHalfUp, jan, "P1D"},
+		{"P1DT12H", period.Day, period.RoundHalfUp, jan, "P2D"},
+		{"PT1H29M45S", period.Minute, period.RoundHalfUp, jan, "PT1H30M"},
+		{"PT1H59M45S", period.Minute, period.RoundHalfUp, jan, "PT2H"},
+		{"P11M20D", period.Month, period.RoundHalfUp, jan, "P1Y"},
+	}
+
+	for i, test := range tests {
+		got, err := period.MustParse(test.period, false).RoundAt(test.unit, test.mode, test.ref)
+		is.NoErr(err)
+		is.Equal(info(i, got.String()), info(i, test.want))
+	}
+
+	got, err := period.MustParse("P1M30D", false).TruncateAt(period.Month, jan)
+	is.NoErr(err)
+	is.Equal(got.String(), "P2M")
+	got, err = period.MustParse("P1M30D", false).TruncateAt(period.Month, jul)
+	is.NoErr(err)
+	is.Equal(got.String(), "P1M")
+}
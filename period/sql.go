@@ -0,0 +1,335 @@
+package period
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/imarsman/datetime/xfmt"
+)
+
+// Scan implements the sql.Scanner interface for PostgreSQL interval columns.
+// All interval output styles are accepted:
+//
+//	postgres          1 year 2 mons 3 days 04:05:06.789
+//	postgres_verbose  @ 1 year 2 mons 3 days 4 hours 5 mins 6.789 secs ago
+//	sql_standard      1-2 3 4:05:06.789
+//	iso_8601          P1Y2M3DT4H5M6.789S
+//
+// Components can have differing signs, such as "1 year -2 mons", giving a
+// period with mixed signs. A nil source gives the zero period.
+func (period *Period) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*period = Period{}
+		return nil
+	case []byte:
+		return period.scanInterval(string(v))
+	case string:
+		return period.scanInterval(v)
+	}
+
+	return fmt.Errorf("period.Scan: unsupported source type %T", src)
+}
+
+// Value implements the driver.Valuer interface. The period is written in
+// ISO-8601 form. Negative periods have each component negated, e.g.
+// "P-1Y-2M", as this is the form PostgreSQL accepts. PostgreSQL stores
+// intervals to microsecond precision.
+func (period Period) Value() (driver.Value, error) {
+	return string(period.appendComponentSigned(nil)), nil
+}
+
+// appendComponentSigned writes the period with a sign on each component
+// rather than a leading sign.
+func (period Period) appendComponentSigned(b []byte) []byte {
+	if !period.IsNegative() {
+		return period.AppendString(b)
+	}
+
+	fields := period.signedFields()
+	signed := Period{
+		years:       fields[0],
+		months:      fields[1],
+		weeks:       fields[2],
+		days:        fields[3],
+		hours:       fields[4],
+		minutes:     fields[5],
+		seconds:     fields[6],
+		nanoseconds: fields[7],
+	}
+
+	return signed.AppendString(b)
+}
+
+// intervalParts holds signed interval components while scanning.
+type intervalParts struct {
+	years, months, days, hours, minutes, seconds, nanoseconds int64
+}
+
+func (ip *intervalParts) period() (Period, error) {
+	return fromSignedFields([8]int64{
+		ip.years, ip.months, 0, ip.days, ip.hours, ip.minutes, ip.seconds, ip.nanoseconds,
+	})
+}
+
+func (ip *intervalParts) negate() {
+	ip.years, ip.months, ip.days = -ip.years, -ip.months, -ip.days
+	ip.hours, ip.minutes, ip.seconds, ip.nanoseconds = -ip.hours, -ip.minutes, -ip.seconds, -ip.nanoseconds
+}
+
+func (period *Period) scanInterval(input string) error {
+	s := strings.TrimSpace(input)
+	if s == "" {
+		return errors.New("period.Scan: cannot scan a blank string as an interval")
+	}
+
+	var p Period
+	var err error
+	if s[0] == periodChar || strings.HasPrefix(s, "-P") || strings.HasPrefix(s, "+P") {
+		p, err = scanISOInterval(s)
+	} else {
+		p, err = scanPostgresInterval(s)
+	}
+	if err != nil {
+		return err
+	}
+
+	*period = p
+
+	return nil
+}
+
+// scanISOInterval parses the iso_8601 style, in which PostgreSQL puts a sign
+// on each negative component.
+func scanISOInterval(s string) (Period, error) {
+	return Parse(s)
+}
+
+// scanPostgresInterval parses the postgres, postgres_verbose and sql_standard
+// styles.
+func scanPostgresInterval(s string) (Period, error) {
+	var ip intervalParts
+
+	fields := strings.Fields(s)
+	if fields[0] == "@" {
+		fields = fields[1:]
+	} else if strings.HasPrefix(fields[0], "@") {
+		fields[0] = fields[0][1:]
+	}
+
+	invalid := func(token string) error {
+		xfmt := new(xfmt.Buffer)
+		xfmt.S("period.Scan: cannot parse ").Q(token).S(" in interval ").Q(s)
+
+		return errors.New(string(xfmt.Bytes()))
+	}
+
+	// components counts the values seen, as "ago" must follow at least one
+	components := 0
+	for i := 0; i < len(fields); i++ {
+		token := fields[i]
+
+		if token == "ago" {
+			// "ago" negates the whole interval, so must end it
+			if i != len(fields)-1 || components == 0 {
+				return Period{}, invalid(token)
+			}
+			ip.negate()
+			continue
+		}
+		components++
+
+		switch {
+		case strings.ContainsRune(token, ':'):
+			// h:mm:ss[.fraction] with one sign for all parts
+			hours, minutes, seconds, nanoseconds, err := scanClock(token)
+			if err != nil {
+				return Period{}, invalid(token)
+			}
+			ip.hours += hours
+			ip.minutes += minutes
+			ip.seconds += seconds
+			ip.nanoseconds += nanoseconds
+
+		case strings.IndexByte(strings.TrimLeft(token, "+-"), '-') > 0:
+			// sql_standard years-months with one sign for both
+			years, months, err := scanYearMonth(token)
+			if err != nil {
+				return Period{}, invalid(token)
+			}
+			ip.years += years
+			ip.months += months
+
+		default:
+			whole, nanoseconds, err := scanNumber(token)
+			if err != nil {
+				return Period{}, invalid(token)
+			}
+
+			var unit string
+			if i+1 < len(fields) && isUnitWord(fields[i+1]) {
+				i++
+				unit = fields[i]
+			}
+
+			switch unit {
+			case "year", "years":
+				ip.years += whole
+			case "mon", "mons", "month", "months":
+				ip.months += whole
+			case "day", "days":
+				ip.days += whole
+			case "hour", "hours":
+				ip.hours += whole
+			case "min", "mins", "minute", "minutes":
+				ip.minutes += whole
+			case "sec", "secs", "second", "seconds":
+				ip.seconds += whole
+				ip.nanoseconds += nanoseconds
+				continue
+			case "":
+				// A bare number before a clock value is a day count in the
+				// sql_standard style, otherwise it is a number of seconds.
+				if i+1 < len(fields) && strings.ContainsRune(fields[i+1], ':') {
+					ip.days += whole
+				} else {
+					ip.seconds += whole
+					ip.nanoseconds += nanoseconds
+					continue
+				}
+			default:
+				return Period{}, invalid(unit)
+			}
+
+			if nanoseconds != 0 {
+				return Period{}, invalid(token)
+			}
+		}
+	}
+
+	if components == 0 {
+		return Period{}, invalid(s)
+	}
+
+	return ip.period()
+}
+
+func isUnitWord(token string) bool {
+	for _, r := range token {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+
+	return token != "ago"
+}
+
+// scanSign removes any leading sign, returning -1 for a minus sign.
+func scanSign(token string) (string, int64) {
+	if strings.HasPrefix(token, "-") {
+		return token[1:], -1
+	}
+
+	return strings.TrimPrefix(token, "+"), 1
+}
+
+// scanNumber parses a signed decimal number into whole units and nanoseconds
+// for the fractional part, which is only meaningful for seconds.
+func scanNumber(token string) (whole, nanoseconds int64, err error) {
+	digits, sign := scanSign(token)
+
+	fraction := ""
+	if dot := strings.IndexByte(digits, dotChar); dot >= 0 {
+		digits, fraction = digits[:dot], digits[dot+1:]
+	}
+	if digits == "" {
+		digits = "0"
+	}
+
+	whole, err = strconv.ParseInt(digits, 10, 64)
+	if err != nil || strings.ContainsAny(digits, "+-") {
+		return 0, 0, errors.New("invalid number")
+	}
+
+	nanoseconds, err = scanFraction(fraction)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return sign * whole, sign * nanoseconds, nil
+}
+
+// scanFraction converts up to nine fractional digits into nanoseconds.
+func scanFraction(fraction string) (int64, error) {
+	if fraction == "" {
+		return 0, nil
+	}
+	if len(fraction) > 9 {
+		fraction = fraction[:9]
+	}
+
+	var nanoseconds int64
+	for i := 0; i < 9; i++ {
+		nanoseconds *= 10
+		if i < len(fraction) {
+			if fraction[i] < '0' || fraction[i] > '9' {
+				return 0, errors.New("invalid fraction")
+			}
+			nanoseconds += int64(fraction[i] - '0')
+		}
+	}
+
+	return nanoseconds, nil
+}
+
+// scanClock parses [+-]h:mm[:ss[.fraction]].
+func scanClock(token string) (hours, minutes, seconds, nanoseconds int64, err error) {
+	digits, sign := scanSign(token)
+
+	parts := strings.Split(digits, ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0, 0, 0, 0, errors.New("invalid clock")
+	}
+
+	hours, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return
+	}
+	minutes, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return
+	}
+	if len(parts) == 3 {
+		seconds, nanoseconds, err = scanNumber(parts[2])
+		if err != nil {
+			return
+		}
+	}
+
+	return sign * hours, sign * minutes, sign * seconds, sign * nanoseconds, nil
+}
+
+// scanYearMonth parses the sql_standard [+-]y-m form.
+func scanYearMonth(token string) (years, months int64, err error) {
+	digits, sign := scanSign(token)
+
+	parts := strings.Split(digits, "-")
+	if len(parts) != 2 {
+		return 0, 0, errors.New("invalid year-month")
+	}
+
+	years, err = strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return
+	}
+	months, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return
+	}
+
+	return sign * years, sign * months, nil
+}
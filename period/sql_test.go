@@ -0,0 +1,120 @@
+package period_test
+
+import (
+	"database/sql/driver"
+	"strings"
+	"testing"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+func TestScanInterval(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		input string
+		want  string
+	}{
+		// postgres
+		{"1 year 2 mons 3 days 04:05:06.789", "P1Y2M3DT4H5M6.789S"},
+		{"-1 years -2 mons -3 days -04:05:06", "-P1Y2M3DT4H5M6S"},
+		{"3 days", "P3D"},
+		{"00:00:00", "P0D"},
+		{"-00:00:01.5", "-PT1.5S"},
+		{"14 mons 00:30:00", "P14MT30M"},
+		// postgres_verbose
+		{"@ 1 year 2 mons 3 days 4 hours 5 mins 6.789 secs", "P1Y2M3DT4H5M6.789S"},
+		{"@ 1 year 2 mons 3 days 4 hours 5 mins 6 secs ago", "-P1Y2M3DT4H5M6S"},
+		{"@ 1 day 2 hours ago", "-P1DT2H"},
+		{"@ 30 secs ago", "-PT30S"},
+		{"@ 0", "P0D"},
+		{"@ 30 mins", "PT30M"},
+		// sql_standard
+		{"1-2 3 4:05:06.789", "P1Y2M3DT4H5M6.789S"},
+		{"-1-2 -3 -4:05:06", "-P1Y2M3DT4H5M6S"},
+		{"1-2", "P1Y2M"},
+		{"3 4:05:06", "P3DT4H5M6S"},
+		{"0", "P0D"},
+		// iso_8601
+		{"P1Y2M3DT4H5M6.789S", "P1Y2M3DT4H5M6.789S"},
+		{"P-1Y-2M-3DT-4H-5M-6S", "-P1Y2M3DT4H5M6S"},
+		{"PT0S", "P0D"},
+		// mixed signs
+		{"1 year -2 mons", "P1Y-2M"},
+		{"1-2 -3 4:05:06", "P1Y2M-3DT4H5M6S"},
+		{"-1 days +02:03:00", "P-1DT2H3M"},
+		{"P1Y-2M", "P1Y-2M"},
+	}
+
+	for i, c := range cases {
+		var p period.Period
+		err := p.Scan(c.input)
+		is.NoErr(err) // interval should scan
+		is.Equal(info(i, p.String()), info(i, c.want))
+
+		var pb period.Period
+		err = pb.Scan([]byte(c.input))
+		is.NoErr(err) // interval bytes should scan
+		is.Equal(pb, p)
+	}
+}
+
+func TestScanIntervalErrors(t *testing.T) {
+	is := is.New(t)
+
+	cases := []interface{}{
+		"",
+		"1 fortnight",
+		"1.5 days",
+		"1:xx:00",
+		"@",
+		"@ ago",
+		"ago",
+		"1 day ago 2 hours",
+		"1 day ago ago",
+		42.0,
+	}
+
+	for i, c := range cases {
+		var p period.Period
+		err := p.Scan(c)
+		t.Log(i, err)
+		is.True(err != nil) // bad interval should not scan
+	}
+
+	var p period.Period
+	err := p.Scan(42.0)
+	is.True(strings.Contains(err.Error(), "float64")) // error should name the type
+
+	is.NoErr(p.Scan(nil))
+	is.True(p.IsZero())
+}
+
+func TestIntervalValue(t *testing.T) {
+	is := is.New(t)
+
+	cases := []struct {
+		value period.Period
+		want  string
+	}{
+		{period.NewPeriod(1, 2, 3, 4, 5, 6), "P1Y2M3DT4H5M6S"},
+		{period.NewPeriod(-1, -2, -3, -4, -5, -6), "P-1Y-2M-3DT-4H-5M-6S"},
+		{period.NewPeriod(0, 0, 0, 0, -30, 0), "PT-30M"},
+		{period.NewPeriod(0, 0, 0, 0, 0, 0), "P0D"},
+		{period.MustParse("-PT1.5S", false), "PT-1.5S"},
+		{period.MustParse("P1Y-2M", false), "P1Y-2M"},
+		{period.MustParse("-P1Y-2M", false), "P-1Y2M"},
+	}
+
+	for i, c := range cases {
+		v, err := c.value.Value()
+		is.NoErr(err)
+		is.Equal(info(i, v), info(i, driver.Value(c.want)))
+
+		// The value should scan back to the same period
+		var p period.Period
+		is.NoErr(p.Scan(v))
+		is.Equal(info(i, p.String()), info(i, c.value.String()))
+	}
+}
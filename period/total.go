@@ -0,0 +1,132 @@
+package period
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/cockroachdb/apd"
+)
+
+// totalContext is used for totals, which are rounded to 34 significant digits
+// as with an IEEE 754 decimal128.
+var totalContext = apd.BaseContext.WithPrecision(34)
+
+// TotalIn gives the length of the period in the unit, measured in the calendar
+// from the reference time; e.g. P1M is 28 days from 1st February 2021 and 31
+// days from 1st March. Years, months and days are counted as whole calendar
+// units from the reference time, with the rest as a fraction of the next unit,
+// so P1DT12H is 1.5 days on most days but about 1.52 days over a daylight
+// saving change. Hours and smaller units are elapsed time.
+//
+// An error is returned if unit is not a single unit or the period cannot be
+// added to the reference time. Use TotalInDecimal for values too large or
+// precise for a float64.
+func (p Period) TotalIn(unit Unit, ref time.Time) (float64, error) {
+	total, err := p.TotalInDecimal(unit, ref)
+	if err != nil {
+		return 0, err
+	}
+	return total.Float64()
+}
+
+// TotalInDecimal gives the length of the period in the unit as with TotalIn,
+// as a decimal rounded to 34 significant digits.
+func (p Period) TotalInDecimal(unit Unit, ref time.Time) (*apd.Decimal, error) {
+	index, err := roundField(unit)
+	if err != nil {
+		return nil, err
+	}
+
+	end, _, err := p.AddTo(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	total := new(apd.Decimal)
+	if unit&(Year|Month|Day) == 0 {
+		elapsed := elapsedBetween(ref, end)
+		_, err = totalContext.Quo(total, elapsed, apd.New(approxWeights[index], 0))
+		return total, err
+	}
+
+	// The whole units are added before dividing so that the total is only
+	// rounded once
+	whole, remainder, length := calendarUnits(ref, end, unit)
+	size := apd.New(int64(length), 0)
+	numerator := new(apd.Decimal)
+	apd.BaseContext.Mul(numerator, apd.New(whole, 0), size)
+	apd.BaseContext.Add(numerator, numerator, apd.New(int64(remainder), 0))
+	_, err = totalContext.Quo(total, numerator, size)
+
+	return total, err
+}
+
+// TotalApprox gives the approximate length of the period in the unit, taking
+// a year as 365.2425 days, a month as 30.436875 days and a day as 24 hours, as
+// with CompareApprox; e.g. P1Y is 12 months and P1M is 30.436875 days.
+//
+// An error is returned if unit is not a single unit. Use TotalApproxDecimal
+// for values too large or precise for a float64.
+func (p Period) TotalApprox(unit Unit) (float64, error) {
+	total, err := p.TotalApproxDecimal(unit)
+	if err != nil {
+		return 0, err
+	}
+	return total.Float64()
+}
+
+// TotalApproxDecimal gives the approximate length of the period in the unit as
+// with TotalApprox, as a decimal rounded to 34 significant digits.
+func (p Period) TotalApproxDecimal(unit Unit) (*apd.Decimal, error) {
+	index, err := roundField(unit)
+	if err != nil {
+		return nil, err
+	}
+
+	total := new(apd.Decimal)
+	_, err = totalContext.Quo(total, p.approxTotal(), apd.New(approxWeights[index], 0))
+
+	return total, err
+}
+
+// calendarUnits counts the whole years, months or days from start to end, as
+// with BetweenUnits, giving also the time left over and the length of the
+// next unit. The count and the time left over are negative when end is before
+// start.
+func calendarUnits(start, end time.Time, unit Unit) (whole int64, remainder, length time.Duration) {
+	index, _ := roundField(unit)
+	whole = BetweenUnits(start, end, unit).signedFields()[index]
+
+	step := int64(1)
+	if end.Before(start) {
+		step = -1
+	}
+	next := func(t time.Time, n int64) time.Time {
+		switch unit {
+		case Year:
+			return t.AddDate(int(n), 0, 0)
+		case Month:
+			return t.AddDate(0, int(n), 0)
+		}
+		return t.AddDate(0, 0, int(n))
+	}
+
+	anchor := next(start, whole)
+	length = next(anchor, step).Sub(anchor)
+	if length < 0 {
+		length = -length
+	}
+
+	return whole, end.Sub(anchor), length
+}
+
+// elapsedBetween gives the nanoseconds from start to end as a decimal.
+func elapsedBetween(start, end time.Time) *apd.Decimal {
+	elapsed := elapsedNanoseconds(start, end)
+
+	// The coefficient of a decimal is never negative
+	d := apd.NewWithBigInt(new(big.Int).Abs(elapsed), 0)
+	d.Negative = elapsed.Sign() < 0
+
+	return d
+}
@@ -0,0 +1,88 @@
+package period_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/matryer/is"
+)
+
+func TestTotalIn(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	feb := time.Date(2021, time.February, 1, 0, 0, 0, 0, time.UTC)
+	mar := time.Date(2021, time.March, 1, 0, 0, 0, 0, time.UTC)
+	// the clocks go forward on 14th March 2021, so that day has 23 hours
+	dst := time.Date(2021, time.March, 14, 0, 0, 0, 0, toronto)
+	beforeDST := dst.AddDate(0, 0, -1)
+
+	tests := []struct {
+		period string
+		unit   period.Unit
+		ref    time.Time
+		want   string
+	}{
+		{"P1M", period.Day, feb, "28"},
+		{"P1M", period.Day, mar, "31"},
+		{"P1M", period.Hour, feb, "672"},
+		{"P1Y", period.Month, feb, "12"},
+		{"P1M14D", period.Month, feb, "1.451612903225806451612903225806452"},
+		{"P1DT12H", period.Day, mar, "1.5"},
+		{"P1DT11H30M", period.Day, beforeDST, "1.5"},
+		{"P1DT12H", period.Day, beforeDST, "1.521739130434782608695652173913043"},
+		{"P1D", period.Hour, dst, "23"},
+		{"-P1D", period.Minute, mar, "-1440"},
+		{"PT1.5S", period.Nanosecond, mar, "1500000000"},
+		{"P1000Y", period.Second, mar, "31556908800"},
+	}
+
+	for i, test := range tests {
+		p := period.MustParse(test.period, false)
+		got, err := p.TotalInDecimal(test.unit, test.ref)
+		is.NoErr(err)
+		got.Reduce(got)
+		is.Equal(info(i, got.Text('f')), info(i, test.want))
+	}
+
+	days, err := period.MustParse("P1M", false).TotalIn(period.Day, feb)
+	is.NoErr(err)
+	is.Equal(days, 28.0)
+
+	_, err = period.MustParse("P1M", false).TotalIn(period.Day|period.Hour, feb)
+	is.True(err != nil)
+}
+
+func TestTotalApprox(t *testing.T) {
+	is := is.New(t)
+
+	tests := []struct {
+		period string
+		unit   period.Unit
+		want   string
+	}{
+		{"P1Y", period.Month, "12"},
+		{"P1Y", period.Day, "365.2425"},
+		{"P1M", period.Day, "30.436875"},
+		{"P1W", period.Hour, "168"},
+		{"PT90M", period.Hour, "1.5"},
+		{"-P1D", period.Second, "-86400"},
+		{"P6M", period.Year, "0.5"},
+		{"P9000000000000000000Y", period.Second, "284012568000000000000000000"},
+	}
+
+	for i, test := range tests {
+		p := period.MustParse(test.period, false)
+		got, err := p.TotalApproxDecimal(test.unit)
+		is.NoErr(err)
+		got.Reduce(got)
+		is.Equal(info(i, got.Text('f')), info(i, test.want))
+	}
+
+	hours, err := period.MustParse("P1DT12H", false).TotalApprox(period.Hour)
+	is.NoErr(err)
+	is.Equal(hours, 36.0)
+}
@@ -1,3 +1,8 @@
+// DateRange needs the date package, which is not on this branch, so it is
+// kept here commented out until that package is available.
+
+package timespan
+
 // // Copyright 2015 Rick Beton. All rights reserved.
 // // Use of this source code is governed by a BSD-style
 // // license that can be found in the LICENSE file.
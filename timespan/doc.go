@@ -6,4 +6,10 @@
 // Both are half-open intervals for which the start is included and the end is excluded.
 // This allows for empty spans and also facilitates aggregating spans together.
 //
+// TimeSpan is built on the timestamp and period packages; it can be formatted and
+// parsed in the iCalendar (RFC5545) "start/end" and "start/period" forms.
+// Interval parses and formats ISO-8601 time intervals in all four of their forms.
+// IntervalSet holds many time spans at once, for union, intersection, difference and
+// complement and for finding the gaps between them.
+// DateRange needs the date package and is not yet available.
 package timespan
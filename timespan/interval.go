@@ -0,0 +1,290 @@
+package timespan
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/imarsman/datetime/period"
+	"github.com/imarsman/datetime/timestamp"
+)
+
+// IntervalForm selects which of the ISO-8601 time interval forms an Interval
+// has.
+type IntervalForm int
+
+const (
+	// StartEnd intervals are written as "start/end".
+	StartEnd IntervalForm = iota
+	// StartPeriod intervals are written as "start/period".
+	StartPeriod
+	// PeriodEnd intervals are written as "period/end".
+	PeriodEnd
+	// PeriodOnly intervals are written as just a period, with no start or end.
+	PeriodOnly
+)
+
+// Interval is an ISO-8601 time interval in any of its four forms. For the
+// StartPeriod and PeriodEnd forms, the missing end or start is found by adding
+// the period, so that Start and End are set for every form but PeriodOnly.
+type Interval struct {
+	// Form selects which of Start, End and Period were given.
+	Form IntervalForm
+	// Start is the start of the interval, which is included.
+	Start time.Time
+	// End is the end of the interval, which is excluded.
+	End time.Time
+	// Period is the period as it was given, keeping any weeks. It is zero in
+	// the StartEnd form.
+	Period period.Period
+}
+
+// ParseInterval parses an ISO-8601 time interval in any of its forms:
+//
+//	2007-03-01T13:00:00Z/2008-05-11T15:30:00Z   start and end
+//	2007-03-01T13:00:00Z/P1Y2M10DT2H30M         start and period
+//	P1Y2M10DT2H30M/2008-05-11T15:30:00Z         period and end
+//	P1Y2M10DT2H30M                              period only
+//
+// The two parts may be separated by "--" instead of "/". In the start and end
+// form, the end may leave out its higher-order fields, which are then taken
+// from the start, as in "2007-12-14T13:30/15:30" or "2008-02-15/03-14"; an end
+// without a zone offset takes that of the start. Times may leave out their
+// seconds or minutes, which are then zero.
+//
+// Times are parsed as with timestamp.ParseISOInLocation, using the location
+// for times without a zone offset. Periods keep any weeks and must not be
+// negative, and the end must not be before the start.
+func ParseInterval(value string, location *time.Location) (Interval, error) {
+	fail := func(reason string) (Interval, error) {
+		return Interval{}, fmt.Errorf("cannot parse %q as an interval: %s", value, reason)
+	}
+
+	first, second, separated := splitInterval(value)
+
+	isPeriod := func(s string) bool {
+		return strings.HasPrefix(s, "P")
+	}
+	parsePeriod := func(s string) (period.Period, error) {
+		p, err := period.ParseWithOptions(s, period.KeepWeeks)
+		if err != nil {
+			return period.Period{}, err
+		}
+		if p.IsNegative() {
+			return period.Period{}, errors.New("the period must not be negative")
+		}
+		return p, nil
+	}
+	parseTime := func(parts isoParts) (time.Time, error) {
+		t, err := timestamp.ParseISOInLocation(parts.String(), location)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return t.In(location), nil
+	}
+
+	var iv Interval
+	var err error
+	switch {
+	case !separated:
+		if !isPeriod(first) {
+			return fail("expected start/end, start/period, period/end or period")
+		}
+		iv.Form = PeriodOnly
+		if iv.Period, err = parsePeriod(first); err != nil {
+			return fail(err.Error())
+		}
+		return iv, nil
+
+	case isPeriod(first) && isPeriod(second):
+		return fail("it has no start or end")
+
+	case isPeriod(first):
+		iv.Form = PeriodEnd
+		if iv.Period, err = parsePeriod(first); err != nil {
+			return fail(err.Error())
+		}
+		if iv.End, err = parseTime(splitISO(second)); err != nil {
+			return fail(err.Error())
+		}
+		back := iv.Period
+		if iv.Start, _, err = back.Negate().AddTo(iv.End); err != nil {
+			return fail(err.Error())
+		}
+
+	case isPeriod(second):
+		iv.Form = StartPeriod
+		if iv.Start, err = parseTime(splitISO(first)); err != nil {
+			return fail(err.Error())
+		}
+		if iv.Period, err = parsePeriod(second); err != nil {
+			return fail(err.Error())
+		}
+		if iv.End, _, err = iv.Period.AddTo(iv.Start); err != nil {
+			return fail(err.Error())
+		}
+
+	default:
+		iv.Form = StartEnd
+		start := splitISO(first)
+		if iv.Start, err = parseTime(start); err != nil {
+			return fail(err.Error())
+		}
+		if iv.End, err = parseTime(start.complete(second)); err != nil {
+			return fail(err.Error())
+		}
+		if iv.End.Before(iv.Start) {
+			return fail("the end must not be before the start")
+		}
+	}
+
+	return iv, nil
+}
+
+// splitInterval splits an interval at its "/" or, failing that, its "--"
+// separator.
+func splitInterval(value string) (first, second string, separated bool) {
+	if i := strings.IndexByte(value, '/'); i >= 0 {
+		return value[:i], value[i+1:], true
+	}
+	if i := strings.Index(value, "--"); i >= 0 {
+		return value[:i], value[i+2:], true
+	}
+	return value, "", false
+}
+
+// isoParts holds the parts of an ISO-8601 time as written, such as "2007-12-14",
+// "13:30", "5" and "Z" for "2007-12-14T13:30:00.5Z".
+type isoParts struct {
+	date, clock, fraction, zone string
+	hasClock                    bool
+}
+
+// splitISO splits an ISO-8601 time into its parts.
+func splitISO(s string) (parts isoParts) {
+	t := strings.IndexByte(s, 'T')
+	if t < 0 {
+		parts.date = s
+		return parts
+	}
+	parts.date, parts.clock, parts.hasClock = s[:t], s[t+1:], true
+
+	// The clock has no signs or letters other than in its zone
+	if strings.HasSuffix(parts.clock, "Z") {
+		parts.clock, parts.zone = parts.clock[:len(parts.clock)-1], "Z"
+	} else if z := strings.LastIndexAny(parts.clock, "+-"); z >= 0 {
+		parts.clock, parts.zone = parts.clock[:z], parts.clock[z:]
+	}
+
+	if f := strings.IndexAny(parts.clock, ".,"); f >= 0 {
+		parts.clock, parts.fraction = parts.clock[:f], parts.clock[f+1:]
+	}
+
+	return parts
+}
+
+// complete gives the parts of an end time written after these start parts,
+// taking any higher-order fields and zone it leaves out from the start. An end
+// without "T" is taken to be a time of day when the start has one and the end
+// has a ':' or no '-', and otherwise a date.
+func (start isoParts) complete(end string) isoParts {
+	var parts isoParts
+	switch {
+	case strings.IndexByte(end, 'T') >= 0:
+		parts = splitISO(end)
+	case start.hasClock && (strings.IndexByte(end, ':') >= 0 || strings.IndexByte(end, '-') < 0):
+		parts = splitISO("T" + end)
+	default:
+		parts = splitISO(end)
+	}
+
+	// Omitted fields are the leading ones, so the end is aligned to the right
+	// of the start
+	if len(parts.date) < len(start.date) {
+		parts.date = start.date[:len(start.date)-len(parts.date)] + parts.date
+	}
+	if parts.hasClock && parts.zone == "" {
+		parts.zone = start.zone
+	}
+
+	return parts
+}
+
+// String gives the time with any seconds or minutes left out written as zero
+// and any comma before the fraction written as a point, as needed by
+// timestamp.ParseISOInLocation.
+func (parts isoParts) String() string {
+	if !parts.hasClock {
+		return parts.date
+	}
+
+	extended := strings.IndexByte(parts.date, '-') >= 0 || strings.IndexByte(parts.clock, ':') >= 0
+	clock := parts.clock
+	if extended {
+		for len(clock) > 0 && len(clock) < len("15:04:05") {
+			clock += ":00"
+		}
+	} else {
+		for len(clock) > 0 && len(clock) < len("150405") {
+			clock += "00"
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(parts.date)
+	b.WriteByte('T')
+	b.WriteString(clock)
+	if parts.fraction != "" {
+		b.WriteByte('.')
+		b.WriteString(parts.fraction)
+	}
+	b.WriteString(parts.zone)
+
+	return b.String()
+}
+
+// TimeSpan gives the time span from the start to the end of the interval. An
+// error is returned for the PeriodOnly form, which has neither, or if the
+// interval is too long for a time.Duration.
+func (iv Interval) TimeSpan() (TimeSpan, error) {
+	if iv.Form == PeriodOnly {
+		return TimeSpan{}, errors.New("an interval with only a period has no start or end")
+	}
+	if d := iv.End.Sub(iv.Start); d == maxDuration || d == minDuration {
+		return TimeSpan{}, errors.New("the interval is too long for a timespan")
+	}
+	return NewTimeSpan(iv.Start, iv.End), nil
+}
+
+// Format formats the interval in its form, with times formatted using the
+// layout and the two parts separated by the separator; e.g. the ISO-8601 form
+// is given by Format(time.RFC3339Nano, "/"). A blank layout defaults to
+// time.RFC3339Nano and a blank separator to "/".
+func (iv Interval) Format(layout, separator string) string {
+	if layout == "" {
+		layout = time.RFC3339Nano
+	}
+	if separator == "" {
+		separator = "/"
+	}
+
+	p := iv.Period
+	switch iv.Form {
+	case PeriodOnly:
+		return p.String()
+	case StartPeriod:
+		return iv.Start.Format(layout) + separator + p.String()
+	case PeriodEnd:
+		return p.String() + separator + iv.End.Format(layout)
+	}
+
+	return iv.Start.Format(layout) + separator + iv.End.Format(layout)
+}
+
+// String gives the interval in ISO-8601 form, such as
+// "2007-03-01T13:00:00Z/P1Y2M10DT2H30M". Times are written as with
+// time.RFC3339Nano.
+func (iv Interval) String() string {
+	return iv.Format(time.RFC3339Nano, "/")
+}
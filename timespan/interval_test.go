@@ -0,0 +1,109 @@
+package timespan
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	toronto, _ := time.LoadLocation("America/Toronto")
+
+	cases := []struct {
+		text     string
+		location *time.Location
+		form     IntervalForm
+		start    time.Time
+		end      time.Time
+		period   string
+		exp      string
+	}{
+		{"2007-03-01T13:00:00Z/2008-05-11T15:30:00Z", time.UTC, StartEnd,
+			time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC), time.Date(2008, 5, 11, 15, 30, 0, 0, time.UTC), "P0D",
+			"2007-03-01T13:00:00Z/2008-05-11T15:30:00Z"},
+		{"2007-03-01T13:00:00Z/P1Y2M10DT2H30M", time.UTC, StartPeriod,
+			time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC), time.Date(2008, 5, 11, 15, 30, 0, 0, time.UTC), "P1Y2M10DT2H30M",
+			"2007-03-01T13:00:00Z/P1Y2M10DT2H30M"},
+		{"P1Y2M10DT2H30M/2008-05-11T15:30:00Z", time.UTC, PeriodEnd,
+			time.Date(2007, 3, 1, 13, 0, 0, 0, time.UTC), time.Date(2008, 5, 11, 15, 30, 0, 0, time.UTC), "P1Y2M10DT2H30M",
+			"P1Y2M10DT2H30M/2008-05-11T15:30:00Z"},
+		{"P1W", time.UTC, PeriodOnly, time.Time{}, time.Time{}, "P1W", "P1W"},
+		// abbreviated ends
+		{"2007-12-14T13:30/15:30", time.UTC, StartEnd,
+			time.Date(2007, 12, 14, 13, 30, 0, 0, time.UTC), time.Date(2007, 12, 14, 15, 30, 0, 0, time.UTC), "P0D",
+			"2007-12-14T13:30:00Z/2007-12-14T15:30:00Z"},
+		{"2008-02-15/03-14", time.UTC, StartEnd,
+			time.Date(2008, 2, 15, 0, 0, 0, 0, time.UTC), time.Date(2008, 3, 14, 0, 0, 0, 0, time.UTC), "P0D",
+			"2008-02-15T00:00:00Z/2008-03-14T00:00:00Z"},
+		{"2008-02-15/16", time.UTC, StartEnd,
+			time.Date(2008, 2, 15, 0, 0, 0, 0, time.UTC), time.Date(2008, 2, 16, 0, 0, 0, 0, time.UTC), "P0D",
+			"2008-02-15T00:00:00Z/2008-02-16T00:00:00Z"},
+		{"2007-11-13T09:00:00-05:00/15T17:00", time.UTC, StartEnd,
+			time.Date(2007, 11, 13, 14, 0, 0, 0, time.UTC), time.Date(2007, 11, 15, 22, 0, 0, 0, time.UTC), "P0D",
+			"2007-11-13T14:00:00Z/2007-11-15T22:00:00Z"},
+		{"20071214T1330Z/T1530", time.UTC, StartEnd,
+			time.Date(2007, 12, 14, 13, 30, 0, 0, time.UTC), time.Date(2007, 12, 14, 15, 30, 0, 0, time.UTC), "P0D",
+			"2007-12-14T13:30:00Z/2007-12-14T15:30:00Z"},
+		{"2007-12-14T13:30:00,5/13:30:01", time.UTC, StartEnd,
+			time.Date(2007, 12, 14, 13, 30, 0, 5e8, time.UTC), time.Date(2007, 12, 14, 13, 30, 1, 0, time.UTC), "P0D",
+			"2007-12-14T13:30:00.5Z/2007-12-14T13:30:01Z"},
+		// "--" separator
+		{"2008-02-15--03-14", time.UTC, StartEnd,
+			time.Date(2008, 2, 15, 0, 0, 0, 0, time.UTC), time.Date(2008, 3, 14, 0, 0, 0, 0, time.UTC), "P0D",
+			"2008-02-15T00:00:00Z/2008-03-14T00:00:00Z"},
+		{"2007-12-14T13:30-05:00--P1D", time.UTC, StartPeriod,
+			time.Date(2007, 12, 14, 18, 30, 0, 0, time.UTC), time.Date(2007, 12, 15, 18, 30, 0, 0, time.UTC), "P1D",
+			"2007-12-14T18:30:00Z/P1D"},
+		// the clocks go forward on 8th March 2015, so that day has 23 hours
+		{"P1D/2015-03-09T12:00", toronto, PeriodEnd,
+			time.Date(2015, 3, 8, 12, 0, 0, 0, toronto), time.Date(2015, 3, 9, 12, 0, 0, 0, toronto), "P1D",
+			"P1D/2015-03-09T12:00:00-04:00"},
+		{"2015-03-07T12:00/P2D", toronto, StartPeriod,
+			time.Date(2015, 3, 7, 12, 0, 0, 0, toronto), time.Date(2015, 3, 9, 12, 0, 0, 0, toronto), "P2D",
+			"2015-03-07T12:00:00-05:00/P2D"},
+	}
+
+	for i, c := range cases {
+		iv, err := ParseInterval(c.text, c.location)
+		isEq(t, i, err, nil, c.text)
+		isEq(t, i, iv.Form, c.form, c.text)
+		isEq(t, i, iv.Start.Equal(c.start), true, c.text, iv.Start)
+		isEq(t, i, iv.End.Equal(c.end), true, c.text, iv.End)
+		isEq(t, i, iv.Period.String(), c.period, c.text)
+		isEq(t, i, iv.String(), c.exp, c.text)
+
+		// the ISO-8601 form parses back to the same interval
+		iv2, err := ParseInterval(iv.String(), c.location)
+		isEq(t, i, err, nil, iv.String())
+		isEq(t, i, iv2.String(), iv.String())
+	}
+}
+
+func TestParseIntervalErrors(t *testing.T) {
+	for i, text := range []string{
+		"", "2007-03-01T13:00:00Z", "P1D/P1D", "-P1D", "2007-03-01T13:00:00Z/-P1D",
+		"2008-05-11T15:30:00Z/2007-03-01T13:00:00Z", "2008-02-15/02-14", "2007-12-14T13:30/11:30",
+		"2007-03-01T13:00:00Z/", "/2007-03-01T13:00:00Z", "2007-03-01T13:00:00Z/P1X", "2007-1x-01/P1D",
+	} {
+		_, err := ParseInterval(text, time.UTC)
+		isEq(t, i, err != nil, true, text)
+	}
+}
+
+func TestIntervalFormat(t *testing.T) {
+	iv, err := ParseInterval("2015-03-27T10:13:14Z/PT1H", time.UTC)
+	isEq(t, 0, err, nil)
+	isEq(t, 0, iv.Format(RFC5545DateTimeUTC, " for "), "20150327T101314Z for PT1H")
+	isEq(t, 0, iv.Format("", ""), "2015-03-27T10:13:14Z/PT1H")
+
+	iv.Form = StartEnd
+	isEq(t, 0, iv.Format("2006-01-02 15:04", " to "), "2015-03-27 10:13 to 2015-03-27 11:13")
+
+	ts, err := iv.TimeSpan()
+	isEq(t, 0, err, nil)
+	isEq(t, 0, ts.Duration(), time.Hour)
+
+	iv, err = ParseInterval("PT1H", time.UTC)
+	isEq(t, 0, err, nil)
+	_, err = iv.TimeSpan()
+	isEq(t, 0, err != nil, true)
+}
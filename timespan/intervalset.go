@@ -0,0 +1,256 @@
+package timespan
+
+import (
+	"sort"
+	"time"
+)
+
+// IntervalSet is a set of instants made up of time spans. As with TimeSpan,
+// each span is half-open: its start is included and its end is excluded. The
+// spans are kept sorted and coalesced, so that no two overlap or touch and
+// none is empty.
+//
+// An IntervalSet is immutable; its operations give new sets. The zero value
+// is the empty set.
+type IntervalSet struct {
+	spans []span
+}
+
+// span holds the start and end of a span in an IntervalSet. Unlike TimeSpan,
+// it is not limited to the range of a time.Duration, so coalescing spans
+// cannot overflow.
+type span struct {
+	start, end time.Time
+}
+
+// NewIntervalSet creates a set containing the instants of the time spans,
+// which may overlap or touch and be in any order. Empty spans contain no
+// instants and are dropped. It takes O(n log n) time.
+func NewIntervalSet(spans ...TimeSpan) IntervalSet {
+	list := make([]span, 0, len(spans))
+	for _, ts := range spans {
+		if !ts.IsEmpty() {
+			list = append(list, span{ts.Start(), ts.End()})
+		}
+	}
+
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].start.Before(list[j].start)
+	})
+
+	return IntervalSet{coalesce(list)}
+}
+
+// coalesce merges the overlapping or touching spans of a list sorted by
+// start, in place.
+func coalesce(list []span) []span {
+	if len(list) == 0 {
+		return nil
+	}
+
+	merged := list[:1]
+	for _, s := range list[1:] {
+		last := &merged[len(merged)-1]
+		if s.start.After(last.end) {
+			merged = append(merged, s)
+		} else if s.end.After(last.end) {
+			last.end = s.end
+		}
+	}
+
+	return merged
+}
+
+// Len gives the number of disjoint spans in the set.
+func (is IntervalSet) Len() int {
+	return len(is.spans)
+}
+
+// IsEmpty returns true if the set contains no instants.
+func (is IntervalSet) IsEmpty() bool {
+	return len(is.spans) == 0
+}
+
+// Spans gives the disjoint spans of the set in order. A span longer than a
+// time.Duration allows is limited as with NewTimeSpan.
+func (is IntervalSet) Spans() []TimeSpan {
+	result := make([]TimeSpan, len(is.spans))
+	for i, s := range is.spans {
+		result[i] = NewTimeSpan(s.start, s.end)
+	}
+	return result
+}
+
+// SpansIn gives the disjoint spans of the set in order, with their times in a
+// specified location.
+func (is IntervalSet) SpansIn(loc *time.Location) []TimeSpan {
+	result := is.Spans()
+	for i, ts := range result {
+		result[i] = ts.In(loc)
+	}
+	return result
+}
+
+// Duration gives the total duration of the spans in the set.
+func (is IntervalSet) Duration() time.Duration {
+	var total time.Duration
+	for _, s := range is.spans {
+		total += s.end.Sub(s.start)
+	}
+	return total
+}
+
+// Hull gives the smallest time span that encloses the whole set, as
+// TimeSpan.Merge does for two spans. It is the zero TimeSpan for an empty set.
+func (is IntervalSet) Hull() TimeSpan {
+	if len(is.spans) == 0 {
+		return TimeSpan{}
+	}
+	return NewTimeSpan(is.spans[0].start, is.spans[len(is.spans)-1].end)
+}
+
+// Contains tests whether a given moment of time is in the set. It takes
+// O(log n) time.
+func (is IntervalSet) Contains(t time.Time) bool {
+	// the first span that ends after t is the only one that may contain it
+	i := sort.Search(len(is.spans), func(i int) bool {
+		return is.spans[i].end.After(t)
+	})
+	return i < len(is.spans) && !is.spans[i].start.After(t)
+}
+
+// Add gives the set with the time spans added.
+func (is IntervalSet) Add(spans ...TimeSpan) IntervalSet {
+	return is.Union(NewIntervalSet(spans...))
+}
+
+// Union gives the instants that are in either set.
+func (is IntervalSet) Union(other IntervalSet) IntervalSet {
+	list := make([]span, 0, len(is.spans)+len(other.spans))
+	i, j := 0, 0
+	for i < len(is.spans) || j < len(other.spans) {
+		if j == len(other.spans) || (i < len(is.spans) && is.spans[i].start.Before(other.spans[j].start)) {
+			list = append(list, is.spans[i])
+			i++
+		} else {
+			list = append(list, other.spans[j])
+			j++
+		}
+	}
+
+	return IntervalSet{coalesce(list)}
+}
+
+// Intersect gives the instants that are in both sets.
+func (is IntervalSet) Intersect(other IntervalSet) IntervalSet {
+	var list []span
+	i, j := 0, 0
+	for i < len(is.spans) && j < len(other.spans) {
+		a, b := is.spans[i], other.spans[j]
+		start, end := later(a.start, b.start), earlier(a.end, b.end)
+		if start.Before(end) {
+			list = append(list, span{start, end})
+		}
+
+		// the span that ends first cannot overlap any later spans of the other set
+		if a.end.Before(b.end) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return IntervalSet{list}
+}
+
+// Difference gives the instants that are in this set but not the other.
+func (is IntervalSet) Difference(other IntervalSet) IntervalSet {
+	var list []span
+	j := 0
+	for _, s := range is.spans {
+		start := s.start
+		// skip the spans of the other set that end before this one starts
+		for j < len(other.spans) && !other.spans[j].end.After(start) {
+			j++
+		}
+
+		// cut out each span of the other set that starts before this one ends;
+		// the last of them may also overlap the next span of this set, so is
+		// not skipped
+		k := j
+		for k < len(other.spans) && other.spans[k].start.Before(s.end) {
+			if start.Before(other.spans[k].start) {
+				list = append(list, span{start, other.spans[k].start})
+			}
+			start = later(start, other.spans[k].end)
+			k++
+		}
+
+		if start.Before(s.end) {
+			list = append(list, span{start, s.end})
+		}
+	}
+
+	return IntervalSet{list}
+}
+
+// Complement gives the instants within the bounds that are not in the set.
+func (is IntervalSet) Complement(bounds TimeSpan) IntervalSet {
+	return NewIntervalSet(bounds).Difference(is)
+}
+
+// Gaps gives the instants between the first and last spans of the set that
+// are not in the set.
+func (is IntervalSet) Gaps() IntervalSet {
+	if len(is.spans) < 2 {
+		return IntervalSet{}
+	}
+
+	list := make([]span, len(is.spans)-1)
+	for i := range list {
+		list[i] = span{is.spans[i].end, is.spans[i+1].start}
+	}
+
+	return IntervalSet{list}
+}
+
+// Equal tests whether two sets contain the same instants.
+func (is IntervalSet) Equal(other IntervalSet) bool {
+	if len(is.spans) != len(other.spans) {
+		return false
+	}
+	for i, s := range is.spans {
+		if !s.start.Equal(other.spans[i].start) || !s.end.Equal(other.spans[i].end) {
+			return false
+		}
+	}
+	return true
+}
+
+// String produces a human-readable description of the set, listing its spans.
+func (is IntervalSet) String() string {
+	b := make([]byte, 0, 64*len(is.spans)+2)
+	b = append(b, '[')
+	for i, ts := range is.Spans() {
+		if i > 0 {
+			b = append(b, ", "...)
+		}
+		b = append(b, ts.String()...)
+	}
+	b = append(b, ']')
+	return string(b)
+}
+
+func earlier(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func later(a, b time.Time) time.Time {
+	if b.After(a) {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,129 @@
+package timespan
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+var t0900 = time.Date(2015, 3, 27, 9, 0, 0, 0, time.UTC)
+
+// hours gives a set of spans from the given pairs of hours after 09:00.
+func hours(pairs ...int) IntervalSet {
+	var spans []TimeSpan
+	for i := 0; i < len(pairs); i += 2 {
+		spans = append(spans, NewTimeSpan(t0900.Add(time.Duration(pairs[i])*time.Hour), t0900.Add(time.Duration(pairs[i+1])*time.Hour)))
+	}
+	return NewIntervalSet(spans...)
+}
+
+// clock describes a set by the clock times of its spans.
+func clock(is IntervalSet) string {
+	var parts []string
+	for _, ts := range is.Spans() {
+		parts = append(parts, ts.Start().Format("15:04")+"-"+ts.End().Format("15:04"))
+	}
+	return strings.Join(parts, " ")
+}
+
+func TestNewIntervalSet(t *testing.T) {
+	cases := []struct {
+		set IntervalSet
+		exp string
+	}{
+		{NewIntervalSet(), ""},
+		{IntervalSet{}, ""},
+		{hours(0, 0), ""},
+		{hours(0, 1), "09:00-10:00"},
+		{hours(2, 3, 0, 1), "09:00-10:00 11:00-12:00"},
+		{hours(0, 2, 1, 3), "09:00-12:00"},
+		{hours(0, 1, 1, 2), "09:00-11:00"},
+		{hours(0, 4, 1, 2), "09:00-13:00"},
+		{hours(3, 4, 0, 1, 1, 2, 6, 7), "09:00-11:00 12:00-13:00 15:00-16:00"},
+		// spans may be given with their end first
+		{hours(1, 0, 3, 2), "09:00-10:00 11:00-12:00"},
+	}
+
+	for i, c := range cases {
+		isEq(t, i, clock(c.set), c.exp)
+		isEq(t, i, c.set.IsEmpty(), c.exp == "")
+	}
+
+	is := hours(3, 4, 0, 1, 1, 2)
+	isEq(t, 0, is.Len(), 2)
+	isEq(t, 0, is.Duration(), 3*time.Hour)
+	isEq(t, 0, is.Hull().Equal(NewTimeSpan(t0900, t0900.Add(4*time.Hour))), true)
+	isEq(t, 0, IntervalSet{}.Hull().IsEmpty(), true)
+}
+
+func TestIntervalSetContains(t *testing.T) {
+	is := hours(0, 1, 2, 3)
+	isEq(t, 0, is.Contains(t0900.Add(minusOneNano)), false)
+	isEq(t, 0, is.Contains(t0900), true)
+	isEq(t, 0, is.Contains(t0900.Add(time.Hour+minusOneNano)), true)
+	isEq(t, 0, is.Contains(t0900.Add(time.Hour)), false)
+	isEq(t, 0, is.Contains(t0900.Add(2*time.Hour)), true)
+	isEq(t, 0, is.Contains(t0900.Add(3*time.Hour)), false)
+	isEq(t, 0, IntervalSet{}.Contains(t0900), false)
+}
+
+func TestIntervalSetAlgebra(t *testing.T) {
+	cases := []struct {
+		a, b                                 IntervalSet
+		union, intersect, difference, bMinus string
+	}{
+		{hours(0, 2), hours(1, 3), "09:00-12:00", "10:00-11:00", "09:00-10:00", "11:00-12:00"},
+		{hours(0, 1), hours(1, 2), "09:00-11:00", "", "09:00-10:00", "10:00-11:00"},
+		{hours(0, 1), hours(2, 3), "09:00-10:00 11:00-12:00", "", "09:00-10:00", "11:00-12:00"},
+		{hours(0, 4), hours(1, 2), "09:00-13:00", "10:00-11:00", "09:00-10:00 11:00-13:00", ""},
+		{hours(0, 8), hours(1, 2, 3, 4, 5, 6), "09:00-17:00", "10:00-11:00 12:00-13:00 14:00-15:00",
+			"09:00-10:00 11:00-12:00 13:00-14:00 15:00-17:00", ""},
+		{hours(0, 2, 3, 5), hours(1, 4), "09:00-14:00", "10:00-11:00 12:00-13:00", "09:00-10:00 13:00-14:00",
+			"11:00-12:00"},
+		{hours(0, 2, 4, 6), hours(0, 2, 4, 6), "09:00-11:00 13:00-15:00", "09:00-11:00 13:00-15:00", "", ""},
+		{hours(0, 1), IntervalSet{}, "09:00-10:00", "", "09:00-10:00", ""},
+	}
+
+	for i, c := range cases {
+		isEq(t, i, clock(c.a.Union(c.b)), c.union)
+		isEq(t, i, clock(c.b.Union(c.a)), c.union)
+		isEq(t, i, clock(c.a.Intersect(c.b)), c.intersect)
+		isEq(t, i, clock(c.b.Intersect(c.a)), c.intersect)
+		isEq(t, i, clock(c.a.Difference(c.b)), c.difference)
+		isEq(t, i, clock(c.b.Difference(c.a)), c.bMinus)
+	}
+}
+
+func TestIntervalSetComplementAndGaps(t *testing.T) {
+	is := hours(1, 2, 3, 4, 4, 5)
+	day := NewTimeSpan(t0900, t0900.Add(8*time.Hour))
+
+	isEq(t, 0, clock(is.Complement(day)), "09:00-10:00 11:00-12:00 14:00-17:00")
+	isEq(t, 0, clock(is.Gaps()), "11:00-12:00")
+	isEq(t, 0, clock(hours(0, 1).Gaps()), "")
+	isEq(t, 0, clock(IntervalSet{}.Complement(day)), "09:00-17:00")
+
+	// the complement and the set together make up the bounds
+	isEq(t, 0, is.Complement(day).Union(is).Equal(NewIntervalSet(day)), true)
+}
+
+func TestIntervalSetAdd(t *testing.T) {
+	is := hours(0, 1)
+	added := is.Add(NewTimeSpan(t0900.Add(3*time.Hour), t0900.Add(4*time.Hour)), NewTimeSpan(t0900.Add(time.Hour), t0900.Add(2*time.Hour)))
+	isEq(t, 0, clock(added), "09:00-11:00 12:00-13:00")
+	// the original set is unchanged
+	isEq(t, 0, clock(is), "09:00-10:00")
+	isEq(t, 0, added.Equal(hours(0, 2, 3, 4)), true)
+	isEq(t, 0, added.Equal(hours(0, 2)), false)
+}
+
+func TestIntervalSetSpansIn(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	is := hours(0, 1, 2, 3)
+
+	spans := is.SpansIn(berlin)
+	isEq(t, 0, len(spans), 2)
+	isEq(t, 0, spans[0].Format("15:04", "-", false), "10:00-11:00")
+	isEq(t, 1, spans[1].Format("15:04", "-", false), "12:00-13:00")
+	isEq(t, 1, spans[1].Start().Equal(t0900.Add(2*time.Hour)), true)
+}
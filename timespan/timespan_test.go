@@ -1,341 +1,407 @@
-// // Copyright 2015 Rick Beton. All rights reserved.
-// // Use of this source code is governed by a BSD-style
-// // license that can be found in the LICENSE file.
-
-// package timespan
-
-// import (
-// 	"testing"
-// 	"time"
-
-// 	"github.com/imarsman/datetime/date"
-// )
-
-// const zero time.Duration = 0
-
-// var t0327 = time.Date(2015, 3, 27, 0, 0, 0, 0, time.UTC)
-// var t0328 = time.Date(2015, 3, 28, 0, 0, 0, 0, time.UTC)
-// var t0329 = time.Date(2015, 3, 29, 0, 0, 0, 0, time.UTC) // n.b. clocks go forward (UK)
-// var t0330 = time.Date(2015, 3, 30, 0, 0, 0, 0, time.UTC)
-
-// func TestZeroTimeSpan(t *testing.T) {
-// 	ts := ZeroTimeSpan(t0327)
-// 	isEq(t, 0, ts.mark, t0327)
-// 	isEq(t, 0, ts.Duration(), zero)
-// 	isEq(t, 0, ts.End(), t0327)
-// }
-
-// func TestNewTimeSpan(t *testing.T) {
-// 	ts1 := NewTimeSpan(t0327, t0327)
-// 	isEq(t, 0, ts1.mark, t0327)
-// 	isEq(t, 0, ts1.Duration(), zero)
-// 	isEq(t, 0, ts1.IsEmpty(), true)
-// 	isEq(t, 0, ts1.End(), t0327)
-
-// 	ts2 := NewTimeSpan(t0327, t0328)
-// 	isEq(t, 0, ts2.mark, t0327)
-// 	isEq(t, 0, ts2.Duration(), time.Hour*24)
-// 	isEq(t, 0, ts2.IsEmpty(), false)
-// 	isEq(t, 0, ts2.End(), t0328)
-
-// 	ts3 := NewTimeSpan(t0329, t0327)
-// 	isEq(t, 0, ts3.mark, t0327)
-// 	isEq(t, 0, ts3.Duration(), time.Hour*48)
-// 	isEq(t, 0, ts3.IsEmpty(), false)
-// 	isEq(t, 0, ts3.End(), t0329)
-// }
-
-// func TestTSEnd(t *testing.T) {
-// 	ts1 := TimeSpan{t0328, time.Hour * 24}
-// 	isEq(t, 0, ts1.Start(), t0328)
-// 	isEq(t, 0, ts1.End(), t0329)
-
-// 	// not normalised, deliberately
-// 	ts2 := TimeSpan{t0328, -time.Hour * 24}
-// 	isEq(t, 0, ts2.Start(), t0327)
-// 	isEq(t, 0, ts2.End(), t0328)
-// }
-
-// func TestTSShiftBy(t *testing.T) {
-// 	ts1 := NewTimeSpan(t0327, t0328).ShiftBy(time.Hour * 24)
-// 	isEq(t, 0, ts1.mark, t0328)
-// 	isEq(t, 0, ts1.Duration(), time.Hour*24)
-// 	isEq(t, 0, ts1.End(), t0329)
-
-// 	ts2 := NewTimeSpan(t0328, t0329).ShiftBy(-time.Hour * 24)
-// 	isEq(t, 0, ts2.mark, t0327)
-// 	isEq(t, 0, ts2.Duration(), time.Hour*24)
-// 	isEq(t, 0, ts2.End(), t0328)
-// }
-
-// func TestTSExtendBy(t *testing.T) {
-// 	ts1 := NewTimeSpan(t0327, t0328).ExtendBy(time.Hour * 24)
-// 	isEq(t, 0, ts1.mark, t0327)
-// 	isEq(t, 0, ts1.Duration(), time.Hour*48)
-// 	isEq(t, 0, ts1.End(), t0329)
-
-// 	ts2 := NewTimeSpan(t0328, t0329).ExtendBy(-time.Hour * 48)
-// 	isEq(t, 0, ts2.mark, t0327)
-// 	isEq(t, 0, ts2.Duration(), time.Hour*24)
-// 	isEq(t, 0, ts2.End(), t0328)
-// }
-
-// func TestTSExtendWithoutWrapping(t *testing.T) {
-// 	ts1 := NewTimeSpan(t0327, t0328).ExtendWithoutWrapping(time.Hour * 24)
-// 	isEq(t, 0, ts1.mark, t0327)
-// 	isEq(t, 0, ts1.Duration(), time.Hour*48)
-// 	isEq(t, 0, ts1.End(), t0329)
-
-// 	ts2 := NewTimeSpan(t0328, t0329).ExtendWithoutWrapping(-time.Hour * 48)
-// 	isEq(t, 0, ts2.mark, t0328)
-// 	isEq(t, 0, ts2.Duration(), zero)
-// 	isEq(t, 0, ts2.End(), t0328)
-// }
-
-// func TestTSString(t *testing.T) {
-// 	s := NewTimeSpan(t0327, t0328).String()
-// 	isEq(t, 0, s, "24h0m0s from 2015-03-27 00:00:00 to 2015-03-28 00:00:00")
-// }
-
-// func TestTSEqual(t *testing.T) {
-// 	// use Berlin, which is UTC+1/+2
-// 	berlin, _ := time.LoadLocation("Europe/Berlin")
-// 	t0 := time.Date(2015, 2, 20, 10, 13, 25, 0, time.UTC)
-// 	t1 := t0.Add(time.Hour)
-// 	z0 := ZeroTimeSpan(t0)
-// 	ts1 := z0.ExtendBy(time.Hour)
-
-// 	cases := []struct {
-// 		a, b TimeSpan
-// 	}{
-// 		{z0, NewTimeSpan(t0, t0)},
-// 		{z0, z0.In(berlin)},
-// 		{ts1, ts1},
-// 		{ts1, NewTimeSpan(t0, t1)},
-// 		{ts1, ts1.In(berlin)},
-// 		{ts1, ZeroTimeSpan(t1).ExtendBy(-time.Hour)},
-// 	}
-
-// 	for i, c := range cases {
-// 		if !c.a.Equal(c.b) {
-// 			t.Errorf("%d: %v is not equal to %v", i, c.a, c.b)
-// 		}
-// 	}
-// }
-
-// func TestTSNotEqual(t *testing.T) {
-// 	t0 := time.Date(2015, 2, 20, 10, 13, 25, 0, time.UTC)
-// 	t1 := t0.Add(time.Hour)
-
-// 	cases := []struct {
-// 		a, b TimeSpan
-// 	}{
-// 		{ZeroTimeSpan(t0), NewTimeSpanOf(t0, time.Hour)},
-// 		{ZeroTimeSpan(t0), ZeroTimeSpan(t1)},
-// 	}
-
-// 	for i, c := range cases {
-// 		if c.a.Equal(c.b) {
-// 			t.Errorf("%d: %v is not equal to %v", i, c.a, c.b)
-// 		}
-// 	}
-// }
-
-// func TestTSFormat(t *testing.T) {
-// 	// use Berlin, which is UTC-1
-// 	berlin, _ := time.LoadLocation("Europe/Berlin")
-// 	t0 := time.Date(2015, 3, 27, 10, 13, 14, 0, time.UTC)
-
-// 	cases := []struct {
-// 		start                  time.Time
-// 		duration               time.Duration
-// 		useDuration            bool
-// 		layout, separator, exp string
-// 	}{
-// 		{t0, time.Hour, true, "", " for ", "20150327T101314Z for PT1H"},
-// 		{t0, time.Hour, true, "", "/", "20150327T101314Z/PT1H"},
-// 		{t0.In(berlin), time.Minute, true, "", "/", "20150327T111314/PT1M"},
-// 		{t0.In(berlin), time.Hour, true, "2006-01-02T15:04:05", "/", "2015-03-27T11:13:14/PT1H"},
-// 		{t0.In(berlin), time.Hour, true, "2006-01-02T15:04:05-07", "/", "2015-03-27T11:13:14+01/PT1H"},
-// 		{t0, time.Hour, true, "2006-01-02T15:04:05-07", "/", "2015-03-27T10:13:14+00/PT1H"},
-// 		{t0, time.Hour, true, "2006-01-02T15:04:05Z07", "/", "2015-03-27T10:13:14Z/PT1H"},
-
-// 		{t0, time.Hour, false, "", " to ", "20150327T101314Z to 20150327T111314Z"},
-// 		{t0, time.Hour, false, "", "/", "20150327T101314Z/20150327T111314Z"},
-// 		{t0.In(berlin), time.Minute, false, "", "/", "20150327T111314/20150327T111414"},
-// 		{t0.In(berlin), time.Hour, false, "2006-01-02T15:04:05", "/", "2015-03-27T11:13:14/2015-03-27T12:13:14"},
-// 		{t0.In(berlin), time.Hour, false, "2006-01-02T15:04:05-07", "/", "2015-03-27T11:13:14+01/2015-03-27T12:13:14+01"},
-// 		{t0, time.Hour, false, "2006-01-02T15:04:05-07", "/", "2015-03-27T10:13:14+00/2015-03-27T11:13:14+00"},
-// 		{t0, time.Hour, false, "2006-01-02T15:04:05Z07", "/", "2015-03-27T10:13:14Z/2015-03-27T11:13:14Z"},
-// 	}
-
-// 	for _, c := range cases {
-// 		ts := TimeSpan{c.start, c.duration}
-// 		isEq(t, 0, ts.Format(c.layout, c.separator, c.useDuration), c.exp)
-// 	}
-// }
-
-// func TestTSMarshalText(t *testing.T) {
-// 	// use Berlin, which is UTC+1 or +2 in summer
-// 	berlin, _ := time.LoadLocation("Europe/Berlin")
-// 	t0 := time.Date(2015, 2, 14, 10, 13, 14, 0, time.UTC)
-// 	t1 := time.Date(2015, 6, 27, 10, 13, 15, 0, time.UTC)
-
-// 	cases := []struct {
-// 		start    time.Time
-// 		duration time.Duration
-// 		exp      string
-// 	}{
-// 		{t0, time.Hour, "20150214T101314Z/PT1H"},
-// 		{t1, 2 * time.Hour, "20150627T101315Z/PT2H"},
-// 		{t0.In(berlin), time.Minute, "20150214T111314Z/PT1M"}, // UTC+1
-// 		{t1.In(berlin), time.Second, "20150627T121315Z/PT1S"}, // UTC+2
-// 	}
-
-// 	for i, c := range cases {
-// 		ts := TimeSpan{c.start, c.duration}
-
-// 		s := ts.FormatRFC5545(true)
-// 		isEq(t, i, s, c.exp)
-
-// 		b, err := ts.MarshalText()
-// 		isEq(t, i, err, nil)
-// 		isEq(t, i, string(b), c.exp)
-// 	}
-// }
-
-// func TestTSParseInLocation(t *testing.T) {
-// 	// use Berlin, which is UTC-1
-// 	berlin, _ := time.LoadLocation("Europe/Berlin")
-// 	t0120 := time.Date(2015, 1, 20, 10, 13, 14, 0, time.UTC)
-// 	// just before start of daylight savings
-// 	t0325 := time.Date(2015, 3, 25, 10, 13, 14, 0, time.UTC)
-
-// 	cases := []struct {
-// 		start    time.Time
-// 		duration time.Duration
-// 		text     string
-// 	}{
-// 		{t0325, time.Hour, "20150325T101314Z/PT1H"},
-// 		{t0325, 2 * time.Second, "20150325T101314Z/PT2S"},
-// 		{t0120.In(berlin), time.Minute, "20150120T111314/PT1M"},
-// 		{t0325, 336 * time.Hour, "20150325T101314Z/P2W"},
-// 		{t0120.In(berlin), 72 * time.Hour, "20150120T111314/P3D"},
-// 		// This case has the daylight-savings clock shift
-// 		{t0325.In(berlin), 167 * time.Hour, "20150325T111314/P1W"},
-// 	}
-
-// 	for i, c := range cases {
-// 		ts1, err := ParseRFC5545InLocation(c.text, c.start.Location())
-// 		if err != nil {
-// 			t.Errorf("%d: %s %v %v", i, c.text, ts1.String(), err)
-// 		}
-
-// 		if !ts1.Start().Equal(c.start) {
-// 			t.Errorf("%d: %s", i, ts1)
-// 		}
-
-// 		if ts1.Duration() != c.duration {
-// 			t.Errorf("%d: %s", i, ts1)
-// 		}
-
-// 		ts2 := TimeSpan{}.In(c.start.Location())
-// 		err = ts2.UnmarshalText([]byte(c.text))
-// 		if err != nil {
-// 			t.Errorf("%d: %s: %v %v", i, c.text, ts2.String(), err)
-// 		}
-
-// 		if !ts1.Equal(ts2) {
-// 			t.Errorf("%d: %s: %v is not equal to %v", i, c.text, ts1, ts2)
-// 		}
-// 	}
-// }
-
-// func TestTSParseInLocationErrors(t *testing.T) {
-// 	cases := []struct {
-// 		text string
-// 	}{
-// 		{"20150327T101314Z PT1H"},
-// 		{"2015XX27T101314/PT1H"},
-// 		{"20150127T101314/2016XX27T101314"},
-// 		{"20150127T101314/P1Z"},
-// 		{"20150327T101314Z/"},
-// 		{"/PT1H"},
-// 	}
-
-// 	for _, c := range cases {
-// 		ts, err := ParseRFC5545InLocation(c.text, time.UTC)
-// 		if err == nil {
-// 			t.Errorf(ts.String())
-// 		}
-// 	}
-// }
-
-// func TestTSContains(t *testing.T) {
-// 	ts := NewTimeSpan(t0327, t0329)
-// 	isEq(t, 0, ts.Contains(t0327.Add(minusOneNano)), false)
-// 	isEq(t, 0, ts.Contains(t0327), true)
-// 	isEq(t, 0, ts.Contains(t0328), true)
-// 	isEq(t, 0, ts.Contains(t0329.Add(minusOneNano)), true)
-// 	isEq(t, 0, ts.Contains(t0329), false)
-// }
-
-// func TestTSIn(t *testing.T) {
-// 	ts := ZeroTimeSpan(t0327).In(time.FixedZone("Test", 7200))
-// 	isEq(t, 0, ts.mark.Equal(t0327), true)
-// 	isEq(t, 0, ts.Duration(), zero)
-// 	isEq(t, 0, ts.End().Equal(t0327), true)
-// }
-
-// func TestTSMerge1(t *testing.T) {
-// 	ts1 := NewTimeSpan(t0327, t0328)
-// 	ts2 := NewTimeSpan(t0327, t0330)
-// 	m1 := ts1.Merge(ts2)
-// 	m2 := ts2.Merge(ts1)
-// 	isEq(t, 0, m1.mark, t0327)
-// 	isEq(t, 0, m1.End(), t0330)
-// 	isEq(t, 0, m1, m2)
-// }
-
-// func TestTSMerge2(t *testing.T) {
-// 	ts1 := NewTimeSpan(t0328, t0329)
-// 	ts2 := NewTimeSpan(t0327, t0330)
-// 	m1 := ts1.Merge(ts2)
-// 	m2 := ts2.Merge(ts1)
-// 	isEq(t, 0, m1.mark, t0327)
-// 	isEq(t, 0, m1.End(), t0330)
-// 	isEq(t, 0, m1, m2)
-// }
-
-// func TestTSMerge3(t *testing.T) {
-// 	ts1 := NewTimeSpan(t0329, t0330)
-// 	ts2 := NewTimeSpan(t0327, t0330)
-// 	m1 := ts1.Merge(ts2)
-// 	m2 := ts2.Merge(ts1)
-// 	isEq(t, 0, m1.mark, t0327)
-// 	isEq(t, 0, m1.End(), t0330)
-// 	isEq(t, 0, m1, m2)
-// }
-
-// func TestTSMergeOverlapping(t *testing.T) {
-// 	ts1 := NewTimeSpan(t0327, t0329)
-// 	ts2 := NewTimeSpan(t0328, t0330)
-// 	m1 := ts1.Merge(ts2)
-// 	m2 := ts2.Merge(ts1)
-// 	isEq(t, 0, m1.mark, t0327)
-// 	isEq(t, 0, m1.End(), t0330)
-// 	isEq(t, 0, m1, m2)
-// }
-
-// func TestTSMergeNonOverlapping(t *testing.T) {
-// 	ts1 := NewTimeSpan(t0327, t0328)
-// 	ts2 := NewTimeSpan(t0329, t0330)
-// 	m1 := ts1.Merge(ts2)
-// 	m2 := ts2.Merge(ts1)
-// 	isEq(t, 0, m1.mark, t0327)
-// 	isEq(t, 0, m1.End(), t0330)
-// 	isEq(t, 0, m1, m2)
-// }
+// Copyright 2015 Rick Beton. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package timespan
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const zero time.Duration = 0
+
+const minusOneNano time.Duration = -1
+
+var t0327 = time.Date(2015, 3, 27, 0, 0, 0, 0, time.UTC)
+var t0328 = time.Date(2015, 3, 28, 0, 0, 0, 0, time.UTC)
+var t0329 = time.Date(2015, 3, 29, 0, 0, 0, 0, time.UTC) // n.b. clocks go forward (UK)
+var t0330 = time.Date(2015, 3, 30, 0, 0, 0, 0, time.UTC)
+
+func TestZeroTimeSpan(t *testing.T) {
+	ts := ZeroTimeSpan(t0327)
+	isEq(t, 0, ts.mark, t0327)
+	isEq(t, 0, ts.Duration(), zero)
+	isEq(t, 0, ts.End(), t0327)
+}
+
+func TestNewTimeSpan(t *testing.T) {
+	ts1 := NewTimeSpan(t0327, t0327)
+	isEq(t, 0, ts1.mark, t0327)
+	isEq(t, 0, ts1.Duration(), zero)
+	isEq(t, 0, ts1.IsEmpty(), true)
+	isEq(t, 0, ts1.End(), t0327)
+
+	ts2 := NewTimeSpan(t0327, t0328)
+	isEq(t, 0, ts2.mark, t0327)
+	isEq(t, 0, ts2.Duration(), time.Hour*24)
+	isEq(t, 0, ts2.IsEmpty(), false)
+	isEq(t, 0, ts2.End(), t0328)
+
+	ts3 := NewTimeSpan(t0329, t0327)
+	isEq(t, 0, ts3.mark, t0327)
+	isEq(t, 0, ts3.Duration(), time.Hour*48)
+	isEq(t, 0, ts3.IsEmpty(), false)
+	isEq(t, 0, ts3.End(), t0329)
+}
+
+func TestTSEnd(t *testing.T) {
+	ts1 := TimeSpan{t0328, time.Hour * 24}
+	isEq(t, 0, ts1.Start(), t0328)
+	isEq(t, 0, ts1.End(), t0329)
+
+	// not normalised, deliberately
+	ts2 := TimeSpan{t0328, -time.Hour * 24}
+	isEq(t, 0, ts2.Start(), t0327)
+	isEq(t, 0, ts2.End(), t0328)
+}
+
+func TestTSShiftBy(t *testing.T) {
+	ts1 := NewTimeSpan(t0327, t0328).ShiftBy(time.Hour * 24)
+	isEq(t, 0, ts1.mark, t0328)
+	isEq(t, 0, ts1.Duration(), time.Hour*24)
+	isEq(t, 0, ts1.End(), t0329)
+
+	ts2 := NewTimeSpan(t0328, t0329).ShiftBy(-time.Hour * 24)
+	isEq(t, 0, ts2.mark, t0327)
+	isEq(t, 0, ts2.Duration(), time.Hour*24)
+	isEq(t, 0, ts2.End(), t0328)
+}
+
+func TestTSExtendBy(t *testing.T) {
+	ts1 := NewTimeSpan(t0327, t0328).ExtendBy(time.Hour * 24)
+	isEq(t, 0, ts1.mark, t0327)
+	isEq(t, 0, ts1.Duration(), time.Hour*48)
+	isEq(t, 0, ts1.End(), t0329)
+
+	ts2 := NewTimeSpan(t0328, t0329).ExtendBy(-time.Hour * 48)
+	isEq(t, 0, ts2.mark, t0327)
+	isEq(t, 0, ts2.Duration(), time.Hour*24)
+	isEq(t, 0, ts2.End(), t0328)
+}
+
+func TestTSExtendWithoutWrapping(t *testing.T) {
+	ts1 := NewTimeSpan(t0327, t0328).ExtendWithoutWrapping(time.Hour * 24)
+	isEq(t, 0, ts1.mark, t0327)
+	isEq(t, 0, ts1.Duration(), time.Hour*48)
+	isEq(t, 0, ts1.End(), t0329)
+
+	ts2 := NewTimeSpan(t0328, t0329).ExtendWithoutWrapping(-time.Hour * 48)
+	isEq(t, 0, ts2.mark, t0328)
+	isEq(t, 0, ts2.Duration(), zero)
+	isEq(t, 0, ts2.End(), t0328)
+}
+
+func TestTSString(t *testing.T) {
+	s := NewTimeSpan(t0327, t0328).String()
+	isEq(t, 0, s, "24h0m0s from 2015-03-27 00:00:00 to 2015-03-28 00:00:00")
+}
+
+func TestTSEqual(t *testing.T) {
+	// use Berlin, which is UTC+1/+2
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	t0 := time.Date(2015, 2, 20, 10, 13, 25, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	z0 := ZeroTimeSpan(t0)
+	ts1 := z0.ExtendBy(time.Hour)
+
+	cases := []struct {
+		a, b TimeSpan
+	}{
+		{z0, NewTimeSpan(t0, t0)},
+		{z0, z0.In(berlin)},
+		{ts1, ts1},
+		{ts1, NewTimeSpan(t0, t1)},
+		{ts1, ts1.In(berlin)},
+		{ts1, ZeroTimeSpan(t1).ExtendBy(-time.Hour)},
+	}
+
+	for i, c := range cases {
+		if !c.a.Equal(c.b) {
+			t.Errorf("%d: %v is not equal to %v", i, c.a, c.b)
+		}
+	}
+}
+
+func TestTSNotEqual(t *testing.T) {
+	t0 := time.Date(2015, 2, 20, 10, 13, 25, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+
+	cases := []struct {
+		a, b TimeSpan
+	}{
+		{ZeroTimeSpan(t0), NewTimeSpanOf(t0, time.Hour)},
+		{ZeroTimeSpan(t0), ZeroTimeSpan(t1)},
+	}
+
+	for i, c := range cases {
+		if c.a.Equal(c.b) {
+			t.Errorf("%d: %v is not equal to %v", i, c.a, c.b)
+		}
+	}
+}
+
+func TestTSFormat(t *testing.T) {
+	// use Berlin, which is UTC-1
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	t0 := time.Date(2015, 3, 27, 10, 13, 14, 0, time.UTC)
+
+	cases := []struct {
+		start                  time.Time
+		duration               time.Duration
+		useDuration            bool
+		layout, separator, exp string
+	}{
+		{t0, time.Hour, true, "", " for ", "20150327T101314Z for PT1H"},
+		{t0, time.Hour, true, "", "/", "20150327T101314Z/PT1H"},
+		{t0.In(berlin), time.Minute, true, "", "/", "20150327T111314/PT1M"},
+		{t0.In(berlin), time.Hour, true, "2006-01-02T15:04:05", "/", "2015-03-27T11:13:14/PT1H"},
+		{t0.In(berlin), time.Hour, true, "2006-01-02T15:04:05-07", "/", "2015-03-27T11:13:14+01/PT1H"},
+		{t0, time.Hour, true, "2006-01-02T15:04:05-07", "/", "2015-03-27T10:13:14+00/PT1H"},
+		{t0, time.Hour, true, "2006-01-02T15:04:05Z07", "/", "2015-03-27T10:13:14Z/PT1H"},
+
+		{t0, time.Hour, false, "", " to ", "20150327T101314Z to 20150327T111314Z"},
+		{t0, time.Hour, false, "", "/", "20150327T101314Z/20150327T111314Z"},
+		{t0.In(berlin), time.Minute, false, "", "/", "20150327T111314/20150327T111414"},
+		{t0.In(berlin), time.Hour, false, "2006-01-02T15:04:05", "/", "2015-03-27T11:13:14/2015-03-27T12:13:14"},
+		{t0.In(berlin), time.Hour, false, "2006-01-02T15:04:05-07", "/", "2015-03-27T11:13:14+01/2015-03-27T12:13:14+01"},
+		{t0, time.Hour, false, "2006-01-02T15:04:05-07", "/", "2015-03-27T10:13:14+00/2015-03-27T11:13:14+00"},
+		{t0, time.Hour, false, "2006-01-02T15:04:05Z07", "/", "2015-03-27T10:13:14Z/2015-03-27T11:13:14Z"},
+	}
+
+	for _, c := range cases {
+		ts := TimeSpan{c.start, c.duration}
+		isEq(t, 0, ts.Format(c.layout, c.separator, c.useDuration), c.exp)
+	}
+}
+
+func TestTSMarshalText(t *testing.T) {
+	// use Berlin, which is UTC+1 or +2 in summer
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	t0 := time.Date(2015, 2, 14, 10, 13, 14, 0, time.UTC)
+	t1 := time.Date(2015, 6, 27, 10, 13, 15, 0, time.UTC)
+
+	cases := []struct {
+		start    time.Time
+		duration time.Duration
+		exp      string
+	}{
+		{t0, time.Hour, "20150214T101314Z/PT1H"},
+		{t1, 2 * time.Hour, "20150627T101315Z/PT2H"},
+		{t0.In(berlin), time.Minute, "20150214T101314Z/PT1M"}, // UTC+1
+		{t1.In(berlin), time.Second, "20150627T101315Z/PT1S"}, // UTC+2
+	}
+
+	for i, c := range cases {
+		ts := TimeSpan{c.start, c.duration}
+
+		s := ts.FormatRFC5545(true)
+		isEq(t, i, s, c.exp)
+
+		b, err := ts.MarshalText()
+		isEq(t, i, err, nil)
+		isEq(t, i, string(b), c.exp)
+	}
+}
+
+func TestTSParseInLocation(t *testing.T) {
+	// use Berlin, which is UTC-1
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	t0120 := time.Date(2015, 1, 20, 10, 13, 14, 0, time.UTC)
+	// just before start of daylight savings
+	t0325 := time.Date(2015, 3, 25, 10, 13, 14, 0, time.UTC)
+
+	cases := []struct {
+		start    time.Time
+		duration time.Duration
+		text     string
+	}{
+		{t0325, time.Hour, "20150325T101314Z/PT1H"},
+		{t0325, 2 * time.Second, "20150325T101314Z/PT2S"},
+		{t0120.In(berlin), time.Minute, "20150120T111314/PT1M"},
+		{t0325, 336 * time.Hour, "20150325T101314Z/P2W"},
+		{t0120.In(berlin), 72 * time.Hour, "20150120T111314/P3D"},
+		// This case has the daylight-savings clock shift
+		{t0325.In(berlin), 167 * time.Hour, "20150325T111314/P1W"},
+	}
+
+	for i, c := range cases {
+		ts1, err := ParseRFC5545InLocation(c.text, c.start.Location())
+		if err != nil {
+			t.Errorf("%d: %s %v %v", i, c.text, ts1.String(), err)
+		}
+
+		if !ts1.Start().Equal(c.start) {
+			t.Errorf("%d: %s", i, ts1)
+		}
+
+		if ts1.Duration() != c.duration {
+			t.Errorf("%d: %s", i, ts1)
+		}
+
+		ts2 := TimeSpan{}.In(c.start.Location())
+		err = ts2.UnmarshalText([]byte(c.text))
+		if err != nil {
+			t.Errorf("%d: %s: %v %v", i, c.text, ts2.String(), err)
+		}
+
+		if !ts1.Equal(ts2) {
+			t.Errorf("%d: %s: %v is not equal to %v", i, c.text, ts1, ts2)
+		}
+	}
+}
+
+func TestTSParseInLocationErrors(t *testing.T) {
+	cases := []struct {
+		text string
+	}{
+		{"20150327T101314Z PT1H"},
+		{"2015XX27T101314/PT1H"},
+		{"20150127T101314/2016XX27T101314"},
+		{"20150127T101314/P1Z"},
+		{"20150327T101314Z/"},
+		{"/PT1H"},
+		{"20150327T101314Z"},
+		{"20150327T101314Z/PT1H/PT1H"},
+		{"20150327T101314Z/P9999999999Y"},
+	}
+
+	for _, c := range cases {
+		ts, err := ParseRFC5545InLocation(c.text, time.UTC)
+		if err == nil {
+			t.Errorf(ts.String())
+		}
+	}
+}
+
+func TestTSParseCalendarPeriod(t *testing.T) {
+	toronto, _ := time.LoadLocation("America/Toronto")
+
+	cases := []struct {
+		text     string
+		location *time.Location
+		start    time.Time
+		end      time.Time
+		format   string
+	}{
+		{"20150131T000000Z/P1M", time.UTC,
+			time.Date(2015, 1, 31, 0, 0, 0, 0, time.UTC), time.Date(2015, 3, 3, 0, 0, 0, 0, time.UTC),
+			"20150131T000000Z/P1M"},
+		{"20150201T000000Z/P1M", time.UTC,
+			time.Date(2015, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC),
+			"20150201T000000Z/P1M"},
+		{"20150301T000000Z/P1Y", time.UTC,
+			time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2016, 3, 1, 0, 0, 0, 0, time.UTC),
+			"20150301T000000Z/P1Y"},
+		// the clocks go forward on 8th March 2015, so that day has 23 hours
+		{"20150307T120000/P2D", toronto,
+			time.Date(2015, 3, 7, 12, 0, 0, 0, toronto), time.Date(2015, 3, 9, 12, 0, 0, 0, toronto),
+			"20150307T120000/P2D"},
+	}
+
+	for i, c := range cases {
+		ts, err := ParseRFC5545InLocation(c.text, c.location)
+		isEq(t, i, err, nil, c.text)
+		isEq(t, i, ts.Start().Equal(c.start), true, c.text, ts)
+		isEq(t, i, ts.End().Equal(c.end), true, c.text, ts)
+		isEq(t, i, ts.Format("", "/", true), c.format, c.text)
+	}
+}
+
+func TestTSMarshalTextRoundTrip(t *testing.T) {
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+	t0 := time.Date(2015, 3, 28, 10, 13, 14, 0, time.UTC)
+
+	cases := []TimeSpan{
+		NewTimeSpanOf(t0, time.Hour),
+		NewTimeSpanOf(t0, 90*time.Minute+500*time.Millisecond),
+		NewTimeSpanOf(t0.In(berlin), 48*time.Hour),
+		NewTimeSpan(t0, t0.AddDate(0, 2, 3)),
+		ZeroTimeSpan(t0),
+	}
+
+	for i, ts1 := range cases {
+		b, err := ts1.MarshalText()
+		isEq(t, i, err, nil)
+
+		ts2 := ZeroTimeSpan(time.Time{}.In(time.UTC))
+		err = ts2.UnmarshalText(b)
+		isEq(t, i, err, nil, string(b))
+		isEq(t, i, ts2.Start().Equal(ts1.Start()), true, string(b), ts2)
+		isEq(t, i, ts2.End().Equal(ts1.End()), true, string(b), ts2)
+	}
+}
+
+func TestTSContains(t *testing.T) {
+	ts := NewTimeSpan(t0327, t0329)
+	isEq(t, 0, ts.Contains(t0327.Add(minusOneNano)), false)
+	isEq(t, 0, ts.Contains(t0327), true)
+	isEq(t, 0, ts.Contains(t0328), true)
+	isEq(t, 0, ts.Contains(t0329.Add(minusOneNano)), true)
+	isEq(t, 0, ts.Contains(t0329), false)
+}
+
+func TestTSIn(t *testing.T) {
+	ts := ZeroTimeSpan(t0327).In(time.FixedZone("Test", 7200))
+	isEq(t, 0, ts.mark.Equal(t0327), true)
+	isEq(t, 0, ts.Duration(), zero)
+	isEq(t, 0, ts.End().Equal(t0327), true)
+}
+
+func TestTSMerge1(t *testing.T) {
+	ts1 := NewTimeSpan(t0327, t0328)
+	ts2 := NewTimeSpan(t0327, t0330)
+	m1 := ts1.Merge(ts2)
+	m2 := ts2.Merge(ts1)
+	isEq(t, 0, m1.mark, t0327)
+	isEq(t, 0, m1.End(), t0330)
+	isEq(t, 0, m1, m2)
+}
+
+func TestTSMerge2(t *testing.T) {
+	ts1 := NewTimeSpan(t0328, t0329)
+	ts2 := NewTimeSpan(t0327, t0330)
+	m1 := ts1.Merge(ts2)
+	m2 := ts2.Merge(ts1)
+	isEq(t, 0, m1.mark, t0327)
+	isEq(t, 0, m1.End(), t0330)
+	isEq(t, 0, m1, m2)
+}
+
+func TestTSMerge3(t *testing.T) {
+	ts1 := NewTimeSpan(t0329, t0330)
+	ts2 := NewTimeSpan(t0327, t0330)
+	m1 := ts1.Merge(ts2)
+	m2 := ts2.Merge(ts1)
+	isEq(t, 0, m1.mark, t0327)
+	isEq(t, 0, m1.End(), t0330)
+	isEq(t, 0, m1, m2)
+}
+
+func TestTSMergeOverlapping(t *testing.T) {
+	ts1 := NewTimeSpan(t0327, t0329)
+	ts2 := NewTimeSpan(t0328, t0330)
+	m1 := ts1.Merge(ts2)
+	m2 := ts2.Merge(ts1)
+	isEq(t, 0, m1.mark, t0327)
+	isEq(t, 0, m1.End(), t0330)
+	isEq(t, 0, m1, m2)
+}
+
+func TestTSMergeNonOverlapping(t *testing.T) {
+	ts1 := NewTimeSpan(t0327, t0328)
+	ts2 := NewTimeSpan(t0329, t0330)
+	m1 := ts1.Merge(ts2)
+	m2 := ts2.Merge(ts1)
+	isEq(t, 0, m1.mark, t0327)
+	isEq(t, 0, m1.End(), t0330)
+	isEq(t, 0, m1, m2)
+}
+
+// The conversion tests need DateRange, which is kept commented out until the
+// date package is available.
 
 // func TestConversion1(t *testing.T) {
 // 	ts1 := ZeroTimeSpan(t0327)
@@ -349,7 +415,7 @@
 // 	isEq(t, 0, ts2.Duration(), zero)
 // 	isEq(t, 0, ts1, ts2)
 // }
-
+//
 // func TestConversion2(t *testing.T) {
 // 	ts1 := NewTimeSpan(t0327, t0328)
 // 	dr := ts1.DateRangeIn(time.UTC)
@@ -359,7 +425,7 @@
 // 	isEq(t, 0, ts1, ts2)
 // 	isEq(t, 0, ts1.Duration(), time.Hour*24)
 // }
-
+//
 // func TestConversion3(t *testing.T) {
 // 	dr1 := NewDateRange(d0327, d0330) // weekend of clocks changing
 // 	ts1 := dr1.TimeSpanIn(london)
@@ -372,13 +438,13 @@
 // 	isEq(t, 0, ts1.Duration(), time.Hour*71)
 // }
 
-// // func isEq(t *testing.T, i int, a, b interface{}, msg ...interface{}) {
-// // 	t.Helper()
-// // 	if a != b {
-// // 		sa := make([]string, len(msg))
-// // 		for i, m := range msg {
-// // 			sa[i] = fmt.Sprintf(", %v", m)
-// // 		}
-// // 		t.Errorf("%d: %+v is not equal to %+v%s", i, a, b, strings.Join(sa, ""))
-// // 	}
-// // }
+func isEq(t *testing.T, i int, a, b interface{}, msg ...interface{}) {
+	t.Helper()
+	if a != b {
+		sa := make([]string, len(msg))
+		for i, m := range msg {
+			sa[i] = fmt.Sprintf(", %v", m)
+		}
+		t.Errorf("%d: %+v is not equal to %+v%s", i, a, b, strings.Join(sa, ""))
+	}
+}
@@ -67,24 +67,28 @@ func YearIsBeyondMin(year int64) bool {
 	return year >= MinTimestamp.Unix()
 }
 
-// Int64Overflows does a list of int64s overflow int64?
+// Int64Overflows does a list of int64s overflow int64? The sum is returned
+// with ok set to false if it overflowed.
 func Int64Overflows(int64s ...int64) (sum int64, ok bool) {
+	ok = true
 	for i := 0; i < len(int64s); i++ {
 		sum, ok = overflow.Add64(sum, int64s[i])
 		if ok == false {
-			return sum, true
+			return sum, false
 		}
 	}
 
 	return
 }
 
-// DurationOverflows does a list of durations overflow int64?
+// DurationOverflows does a list of durations overflow int64? The sum is
+// returned with ok set to false if it overflowed.
 func DurationOverflows(durations ...time.Duration) (sum int64, ok bool) {
+	ok = true
 	for i := 0; i < len(durations); i++ {
 		sum, ok = overflow.Add64(sum, int64(durations[i]))
 		if ok == false {
-			return sum, true
+			return sum, false
 		}
 	}
 
@@ -356,6 +360,14 @@ func RFC7232(t time.Time) string {
 	return t.Format(http.TimeFormat)
 }
 
+// AppendRFC7232 appends the RFC7232 form of t to b and returns the extended
+// buffer. As with RFC7232 the time is converted to UTC first. An xfmt.Buffer
+// can be passed and assigned directly since it is a byte slice.
+func AppendRFC7232(b []byte, t time.Time) []byte {
+	t = t.In(time.UTC)
+	return t.AppendFormat(b, http.TimeFormat)
+}
+
 // ISO8601Compact ISO-8601 timestamp with no sub seconds
 //   "20060102T150405-0700"
 //
@@ -365,6 +377,12 @@ func ISO8601Compact(t time.Time) string {
 	return t.Format("20060102T150405-0700")
 }
 
+// AppendISO8601Compact appends the ISO8601Compact form of t to b and returns the extended
+// buffer without allocating when b has enough capacity.
+func AppendISO8601Compact(b []byte, t time.Time) []byte {
+	return t.AppendFormat(b, "20060102T150405-0700")
+}
+
 // ISO8601CompactMsec ISO-8601 timestamp with no seconds
 //   "20060102T150405.000-0700"
 //
@@ -374,6 +392,12 @@ func ISO8601CompactMsec(t time.Time) string {
 	return t.Format("20060102T150405.000-0700")
 }
 
+// AppendISO8601CompactMsec appends the ISO8601CompactMsec form of t to b and returns the extended
+// buffer without allocating when b has enough capacity.
+func AppendISO8601CompactMsec(b []byte, t time.Time) []byte {
+	return t.AppendFormat(b, "20060102T150405.000-0700")
+}
+
 // ISO8601 ISO-8601 timestamp long format string result
 //   "2006-01-02T15:04:05-07:00"
 //
@@ -383,6 +407,12 @@ func ISO8601(t time.Time) string {
 	return t.Format("2006-01-02T15:04:05-07:00")
 }
 
+// AppendISO8601 appends the ISO8601 form of t to b and returns the extended
+// buffer without allocating when b has enough capacity.
+func AppendISO8601(b []byte, t time.Time) []byte {
+	return t.AppendFormat(b, "2006-01-02T15:04:05-07:00")
+}
+
 // ISO8601Msec ISO-8601 longtimestamp with msec
 //   "2006-01-02T15:04:05.000-07:00"
 //
@@ -392,13 +422,25 @@ func ISO8601Msec(t time.Time) string {
 	return t.Format("2006-01-02T15:04:05.000-07:00")
 }
 
+// AppendISO8601Msec appends the ISO8601Msec form of t to b and returns the extended
+// buffer without allocating when b has enough capacity.
+func AppendISO8601Msec(b []byte, t time.Time) []byte {
+	return t.AppendFormat(b, "2006-01-02T15:04:05.000-07:00")
+}
+
 // ISO8601InLocation timestamp long format string result in location
 //   "2006-01-02T15:04:05-07:00"
 //
 // Result will be in whatever the location the incoming time is set to. If UTC
 // is desired set location to time.UTC first
 func ISO8601InLocation(t time.Time, location *time.Location) string {
-	return t.Format("2006-01-02T15:04:05-07:00")
+	return string(AppendISO8601InLocation(make([]byte, 0, 26), t, location))
+}
+
+// AppendISO8601InLocation appends the ISO8601InLocation form of t to b and returns the extended
+// buffer without allocating when b has enough capacity.
+func AppendISO8601InLocation(b []byte, t time.Time, location *time.Location) []byte {
+	return t.AppendFormat(b, "2006-01-02T15:04:05-07:00")
 }
 
 // ISO8601MsecInLocation ISO-8601 longtimestamp with msec in location
@@ -407,7 +449,13 @@ func ISO8601InLocation(t time.Time, location *time.Location) string {
 // Result will be in whatever the location the incoming time is set to. If UTC
 // is desired set location to time.UTC first
 func ISO8601MsecInLocation(t time.Time, location *time.Location) string {
-	return t.Format("2006-01-02T15:04:05.000-07:00")
+	return string(AppendISO8601MsecInLocation(make([]byte, 0, 30), t, location))
+}
+
+// AppendISO8601MsecInLocation appends the ISO8601MsecInLocation form of t to b and returns the extended
+// buffer without allocating when b has enough capacity.
+func AppendISO8601MsecInLocation(b []byte, t time.Time, location *time.Location) []byte {
+	return t.AppendFormat(b, "2006-01-02T15:04:05.000-07:00")
 }
 
 // ISO8601CompactInLocation timestamp with no sub seconds in location
@@ -416,7 +464,13 @@ func ISO8601MsecInLocation(t time.Time, location *time.Location) string {
 // Result will be in whatever the location the incoming time is set to. If UTC
 // is desired set location to time.UTC first
 func ISO8601CompactInLocation(t time.Time, location *time.Location) string {
-	return t.Format("20060102T150405-0700")
+	return string(AppendISO8601CompactInLocation(make([]byte, 0, 21), t, location))
+}
+
+// AppendISO8601CompactInLocation appends the ISO8601CompactInLocation form of t to b and returns the extended
+// buffer without allocating when b has enough capacity.
+func AppendISO8601CompactInLocation(b []byte, t time.Time, location *time.Location) []byte {
+	return t.AppendFormat(b, "20060102T150405-0700")
 }
 
 // ISO8601CompactMsecInLocation timestamp with no seconds in location
@@ -425,7 +479,13 @@ func ISO8601CompactInLocation(t time.Time, location *time.Location) string {
 // Result will be in whatever the location the incoming time is set to. If UTC
 // is desired set location to time.UTC first
 func ISO8601CompactMsecInLocation(t time.Time, location *time.Location) string {
-	return t.Format("20060102T150405.000-0700")
+	return string(AppendISO8601CompactMsecInLocation(make([]byte, 0, 25), t, location))
+}
+
+// AppendISO8601CompactMsecInLocation appends the ISO8601CompactMsecInLocation form of t to b and returns the extended
+// buffer without allocating when b has enough capacity.
+func AppendISO8601CompactMsecInLocation(b []byte, t time.Time, location *time.Location) []byte {
+	return t.AppendFormat(b, "20060102T150405.000-0700")
 }
 
 // StartTimeIsBeforeEndTime if time 1 is before time 2 return true, else false
@@ -1,13 +1,17 @@
 package timestamp_test
 
 import (
+	"database/sql/driver"
+	"encoding/json"
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/imarsman/datetime/timestamp"
+	"github.com/imarsman/datetime/utility"
 	"github.com/imarsman/datetime/xfmt"
 	"github.com/matryer/is"
 )
@@ -621,7 +625,8 @@ func TestISOCompare(t *testing.T) {
 func TestRangeOverTimes(t *testing.T) {
 	is := is.New(t)
 
-	t1 := time.Now()
+	// Use a zone other than UTC so that the zone of t2 is incompatible
+	t1 := time.Now().In(time.FixedZone("X", -4*3600))
 	// Make zone incompatible to test error
 	t2 := t1.Add(10 * 24 * time.Hour).In(time.UTC)
 
@@ -725,6 +730,54 @@ func TestFormat(t *testing.T) {
 	t.Logf("Timestamp %s", s)
 }
 
+// TestAppendFormat check that the append formatters match their string
+// counterparts and can write into an xfmt.Buffer
+func TestAppendFormat(t *testing.T) {
+	is := is.New(t)
+	ts, err := timestamp.ParseInUTC("2006-01-02T15:04:05.123+00:00")
+	is.NoErr(err) // Timestamp should parse with no error
+
+	var b []byte
+	is.Equal(string(timestamp.AppendISO8601(b, ts)), timestamp.ISO8601(ts))
+	is.Equal(string(timestamp.AppendISO8601Msec(b, ts)), timestamp.ISO8601Msec(ts))
+	is.Equal(string(timestamp.AppendISO8601Compact(b, ts)), timestamp.ISO8601Compact(ts))
+	is.Equal(string(timestamp.AppendISO8601CompactMsec(b, ts)), timestamp.ISO8601CompactMsec(ts))
+	is.Equal(string(timestamp.AppendRFC7232(b, ts)), timestamp.RFC7232(ts))
+
+	toronto, _ := time.LoadLocation("America/Toronto")
+	is.Equal(string(timestamp.AppendISO8601InLocation(b, ts, toronto)), timestamp.ISO8601InLocation(ts, toronto))
+	is.Equal(string(timestamp.AppendISO8601MsecInLocation(b, ts, toronto)), timestamp.ISO8601MsecInLocation(ts, toronto))
+	is.Equal(string(timestamp.AppendISO8601CompactInLocation(b, ts, toronto)), timestamp.ISO8601CompactInLocation(ts, toronto))
+	is.Equal(string(timestamp.AppendISO8601CompactMsecInLocation(b, ts, toronto)),
+		timestamp.ISO8601CompactMsecInLocation(ts, toronto))
+	is.Equal(string(timestamp.AppendISO8601CompactMsecInLocation([]byte("at "), ts, toronto)),
+		"at 20060102T150405.123+0000")
+
+	buf := xfmt.Buffer{}
+	buf.S("start ")
+	buf = timestamp.AppendISO8601Msec(buf, ts)
+	buf.S(" end")
+	is.Equal(string(buf.Bytes()), "start 2006-01-02T15:04:05.123+00:00 end")
+}
+
+// Appending into a reused buffer should not allocate
+func BenchmarkAppendISO8601Msec(b *testing.B) {
+	is := is.New(b)
+	ts, err := timestamp.ParseInUTC("2006-01-02T15:04:05.000+00:00")
+	is.NoErr(err) // Timestamp should parse with no error
+
+	buf := make([]byte, 0, 64)
+
+	b.ResetTimer()
+	b.SetBytes(bechmarkBytesPerOp)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf = timestamp.AppendISO8601Msec(buf[:0], ts)
+	}
+
+	is.True(len(buf) > 0)
+}
+
 var locations = []string{
 	"MST",
 	"America/New_York",
@@ -1352,7 +1405,7 @@ func BenchmarkRunesToString(b *testing.B) {
 	b.SetParallelism(30)
 	b.RunParallel(func(pb *testing.PB) {
 		for pb.Next() {
-			s = timestamp.RunesToString(runes...)
+			s = utility.RunesToString(runes...)
 		}
 	})
 
@@ -1377,3 +1430,123 @@ func BenchmarkRunesToStringCast(b *testing.B) {
 
 	is.True(s != "")
 }
+
+// TestTimeWrapper check lenient decoding and layout based encoding of the
+// Time wrapper type
+func TestTimeWrapper(t *testing.T) {
+	is := is.New(t)
+
+	want := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	inputs := []string{
+		`"2006-01-02T15:04:05Z"`,
+		`"2006-01-02T15:04:05.000+00:00"`,
+		`"20060102T150405Z"`,
+		`"Mon, 02 Jan 2006 15:04:05 GMT"`,
+		`"1136214245"`,
+		`1136214245`,
+	}
+
+	for _, input := range inputs {
+		var ts timestamp.Time
+		err := json.Unmarshal([]byte(input), &ts)
+		is.NoErr(err) // Input should decode
+		is.True(ts.Equal(want))
+	}
+
+	type wrapped struct {
+		At timestamp.Time `json:"at"`
+	}
+	b, err := json.Marshal(wrapped{timestamp.NewTime(want.Add(500 * time.Millisecond))})
+	is.NoErr(err)
+	is.Equal(string(b), `{"at":"2006-01-02T15:04:05.5Z"}`)
+
+	var w wrapped
+	err = json.Unmarshal([]byte(`{"at":null}`), &w)
+	is.NoErr(err)
+	is.True(w.At.IsZero())
+
+	var ts timestamp.Time
+	err = ts.UnmarshalText([]byte("2006-01-02 15:04:05"))
+	is.NoErr(err)
+	is.True(ts.Equal(want))
+
+	text, err := ts.MarshalText()
+	is.NoErr(err)
+	is.Equal(string(text), "2006-01-02T15:04:05Z")
+
+	err = ts.UnmarshalText([]byte("not a time"))
+	is.True(err != nil) // Bad input should not decode
+}
+
+// TestTimeWrapperSQL check the sql.Scanner and driver.Valuer implementations
+func TestTimeWrapperSQL(t *testing.T) {
+	is := is.New(t)
+
+	want := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	sources := []interface{}{
+		want,
+		[]byte("2006-01-02T15:04:05Z"),
+		"2006-01-02T15:04:05+00:00",
+		int64(1136214245),
+	}
+
+	for _, src := range sources {
+		var ts timestamp.Time
+		err := ts.Scan(src)
+		is.NoErr(err) // Source should scan
+		is.True(ts.Equal(want))
+
+		v, err := ts.Value()
+		is.NoErr(err)
+		is.Equal(v, driver.Value(ts.Time))
+	}
+
+	var ts timestamp.Time
+	is.NoErr(ts.Scan(nil))
+	is.True(ts.IsZero())
+	v, err := ts.Value()
+	is.NoErr(err)
+	is.Equal(v, nil) // Zero time should be stored as NULL
+
+	err = ts.Scan(3.5)
+	is.True(err != nil)                               // Unsupported type should not scan
+	is.True(strings.Contains(err.Error(), "float64")) // Error should name the type
+}
+
+// TestTimeWrapperLayout check the per value layout and default location
+func TestTimeWrapperLayout(t *testing.T) {
+	is := is.New(t)
+
+	toronto, err := time.LoadLocation("America/Toronto")
+	is.NoErr(err)
+
+	want := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	millis := timestamp.NewTime(want).WithLayout("2006-01-02T15:04:05.000Z07:00")
+
+	b, err := json.Marshal(millis)
+	is.NoErr(err)
+	is.Equal(string(b), `"2006-01-02T15:04:05.000Z"`)
+	is.Equal(millis.String(), "2006-01-02T15:04:05.000Z")
+
+	// the package default is unchanged
+	is.Equal(timestamp.NewTime(want).String(), "2006-01-02T15:04:05Z")
+
+	type wrapped struct {
+		At timestamp.Time `json:"at"`
+	}
+	w := wrapped{timestamp.Time{}.WithLayout("2006-01-02 15:04").WithDefaultLocation(toronto)}
+	err = json.Unmarshal([]byte(`{"at":"2006-01-02T10:04:05"}`), &w)
+	is.NoErr(err)
+	is.True(w.At.Equal(want)) // Zoneless input should use the value's location
+
+	b, err = json.Marshal(w)
+	is.NoErr(err)
+	is.Equal(string(b), `{"at":"2006-01-02 10:04"}`) // Decoding should keep the layout
+
+	var ts timestamp.Time
+	ts = ts.WithDefaultLocation(toronto)
+	is.NoErr(ts.Scan(int64(1136214245)))
+	is.Equal(ts.Location(), toronto)
+}
@@ -0,0 +1,152 @@
+package timestamp
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"time"
+)
+
+// TimeLayout is the default ISO-8601 layout used when marshalling Time values
+// to JSON and text, for values without their own layout from WithLayout. It
+// can be changed to suit, e.g. to "2006-01-02T15:04:05.000Z07:00" for fixed
+// millisecond output, but as it is shared by every importer and read without
+// synchronisation it must only be set during program initialisation.
+var TimeLayout = "2006-01-02T15:04:05.999999999Z07:00"
+
+// TimeLocation is the default location used when unmarshalling or scanning
+// timestamps that have no zone information, for values without their own
+// location from WithDefaultLocation. As with TimeLayout, it must only be set
+// during program initialisation.
+var TimeLocation = time.UTC
+
+// Time wraps time.Time with lenient decoding. JSON, text and SQL inputs are
+// parsed with ParseInLocation so ISO-8601, RFC and Unix timestamp inputs are
+// all accepted. Output uses the layout given by WithLayout, or TimeLayout.
+type Time struct {
+	time.Time
+	layout   string
+	location *time.Location
+}
+
+// NewTime wraps a time.Time value.
+func NewTime(t time.Time) Time {
+	return Time{Time: t}
+}
+
+// WithLayout gives a copy of the time that is marshalled using the layout
+// rather than TimeLayout. Decoding into the copy keeps the layout, so a struct
+// field can be given its own layout before it is unmarshalled.
+func (t Time) WithLayout(layout string) Time {
+	t.layout = layout
+	return t
+}
+
+// WithDefaultLocation gives a copy of the time that uses the location rather
+// than TimeLocation when decoding or scanning timestamps that have no zone
+// information. Decoding into the copy keeps the location.
+func (t Time) WithDefaultLocation(location *time.Location) Time {
+	t.location = location
+	return t
+}
+
+// String formats the time using its layout.
+func (t Time) String() string {
+	return t.Format(t.timeLayout())
+}
+
+// MarshalJSON implements the json.Marshaler interface using the time's layout.
+func (t Time) MarshalJSON() ([]byte, error) {
+	layout := t.timeLayout()
+	b := make([]byte, 0, len(layout)+2)
+	b = append(b, '"')
+	b = t.AppendFormat(b, layout)
+	b = append(b, '"')
+
+	return b, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. Quoted strings and
+// bare Unix timestamp numbers are accepted. A JSON null leaves the value
+// unchanged.
+func (t *Time) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		return nil
+	}
+	if len(s) > 1 && s[0] == '"' && s[len(s)-1] == '"' {
+		s = s[1 : len(s)-1]
+	}
+
+	return t.parse(s)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface using the time's
+// layout.
+func (t Time) MarshalText() ([]byte, error) {
+	layout := t.timeLayout()
+	return t.AppendFormat(make([]byte, 0, len(layout)), layout), nil
+}
+
+// UnmarshalText implements the encoding.TextUnmarshaler interface.
+func (t *Time) UnmarshalText(data []byte) error {
+	return t.parse(string(data))
+}
+
+// Scan implements the sql.Scanner interface. The source can be a time.Time,
+// a []byte or string to be parsed leniently, or an int64 holding seconds since
+// the Unix epoch. A nil source sets the zero time.
+func (t *Time) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		t.Time = time.Time{}
+	case time.Time:
+		t.Time = v
+	case []byte:
+		return t.parse(string(v))
+	case string:
+		return t.parse(v)
+	case int64:
+		t.Time = time.Unix(v, 0).In(t.timeLocation())
+	default:
+		return fmt.Errorf("timestamp.Time.Scan: unsupported source type %T", src)
+	}
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface. The zero time is stored as
+// NULL.
+func (t Time) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+
+	return t.Time, nil
+}
+
+// timeLayout gives the layout of the time, or TimeLayout if it has none.
+func (t Time) timeLayout() string {
+	if t.layout == "" {
+		return TimeLayout
+	}
+	return t.layout
+}
+
+// timeLocation gives the default location of the time, or TimeLocation if it
+// has none.
+func (t Time) timeLocation() *time.Location {
+	if t.location == nil {
+		return TimeLocation
+	}
+	return t.location
+}
+
+func (t *Time) parse(s string) error {
+	parsed, err := ParseInLocation(s, t.timeLocation())
+	if err != nil {
+		return err
+	}
+	t.Time = parsed
+
+	return nil
+}